@@ -0,0 +1,224 @@
+package diceroller
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Results of an attack, the attack roll and the damage it owns.
+type AttackResult struct {
+	hit    *RollResult  // Attack roll result, nil for damage or plain expressions without attack roll
+	damage []RollResult // Damage results, critical when the attack roll scored a critical hit
+}
+
+// Performs an array of RollArgs grouped by attack, such as "hit 1d20+7 dmg 1d8+4 hit 1d20+7 dmg 1d8+4".
+// Returns an AttackResult array and an error array for invalid RollArgs.
+func PerformAttacks(rollArgs ...string) ([]AttackResult, []error) {
+	return defaultRoller.PerformAttacks(rollArgs...)
+}
+
+// Performs an array of RollArgs grouped by attack. Each "hit" expression starts an attack owning the following
+// "dmg" expressions. Damage and plain expressions without attack roll form their own AttackResult.
+// Returns an AttackResult array and an error array for invalid RollArgs.
+func (roller *Roller) PerformAttacks(rollArgs ...string) ([]AttackResult, []error) {
+	results, errs := roller.PerformRollArgs(rollArgs...)
+	return groupAttacks(results), errs
+}
+
+// Groups rollResults by attack.
+func groupAttacks(results []RollResult) (attacks []AttackResult) {
+	for e := range results {
+		switch {
+		case results[e].kind == attackExpression:
+			attacks = append(attacks, AttackResult{&results[e], []RollResult{}})
+		case results[e].kind == damageExpression && len(attacks) > 0 && attacks[len(attacks)-1].owns():
+			attacks[len(attacks)-1].damage = append(attacks[len(attacks)-1].damage, results[e])
+		default:
+			attacks = append(attacks, AttackResult{nil, []RollResult{results[e]}})
+		}
+	}
+	return
+}
+
+// Returns the attack roll result. Returns false for damage or plain expressions without attack roll.
+func (attack AttackResult) HitResult() (RollResult, bool) {
+	if attack.hit == nil {
+		return RollResult{}, false
+	}
+	return *attack.hit, true
+}
+
+// Returns the damage results of the attack.
+func (attack AttackResult) DamageResults() []RollResult {
+	return slices.Clone(attack.damage)
+}
+
+// Returns true if following damage expressions belong to the attack.
+func (attack AttackResult) owns() bool {
+	if attack.hit != nil {
+		return true
+	}
+	// Damage without attack roll keeps grouping damage, plain expressions don't
+	return len(attack.damage) > 0 && attack.damage[0].kind == damageExpression
+}
+
+// Returns true if the attack roll scored a critical hit.
+func (attack AttackResult) CritHit() bool {
+	return attack.hit != nil && attack.hit.CritHit()
+}
+
+// Returns true if the attack roll scored a critical fail.
+func (attack AttackResult) CritFail() bool {
+	return attack.hit != nil && attack.hit.CritFail()
+}
+
+// Returns the attack roll sum, 0 without attack roll.
+func (attack AttackResult) HitSum() int {
+	if attack.hit == nil {
+		return 0
+	}
+	return attack.hit.Sum()
+}
+
+// Returns the total damage of the attack.
+func (attack AttackResult) DamageSum() int {
+	return RollResultsSum(attack.damage...)
+}
+
+// Human readable AttackResult string.
+func (attack AttackResult) String() string {
+	attackStr := "Attack : \n"
+	if attack.hit != nil {
+		attackStr += attack.hit.String()
+	}
+	for d := range attack.damage {
+		attackStr += attack.damage[d].String()
+	}
+	attackStr += fmt.Sprintf("Attack damage: %d \n", attack.DamageSum())
+	return attackStr
+}
+
+// An AttackOutcome is the outcome of an attack roll against an Armor Class.
+type AttackOutcome int
+
+// AttackOutcome values. 0 is invalid.
+const (
+	AttackMiss     AttackOutcome = iota + 1 // Attack roll below the Armor Class
+	AttackHit      AttackOutcome = iota + 1 // Attack roll meeting or beating the Armor Class
+	AttackCritHit  AttackOutcome = iota + 1 // Critical hit, hits whatever the Armor Class
+	AttackCritMiss AttackOutcome = iota + 1 // Critical fail, misses whatever the Armor Class
+)
+
+// AttackOutcome strings.
+var attackOutcomeStrs = map[AttackOutcome]string{
+	AttackMiss:     "miss",
+	AttackHit:      "hit",
+	AttackCritHit:  "critical hit",
+	AttackCritMiss: "critical miss",
+}
+
+// Resolution of an attack against an Armor Class.
+type AttackResolution struct {
+	attack  AttackResult  // Attack roll and damage results, damage is only rolled when the attack hits
+	ac      int           // Armor Class of the target
+	outcome AttackOutcome // Outcome of the attack roll
+}
+
+// Resolves attacks against the Armor Class ac, such as "hit 1d20+7 dmg 1d8+4". Damage is only rolled on hits.
+// Returns an AttackResolution array and an error array for invalid RollArgs.
+func ResolveAttacks(ac int, rollArgs ...string) ([]AttackResolution, []error) {
+	return defaultRoller.ResolveAttacks(ac, rollArgs...)
+}
+
+// Resolves attacks against the Armor Class ac. Each "hit" expression is an attack roll, hitting when its sum
+// meets or beats ac, and the following "dmg" expressions its damage, only rolled on hits. Critical hits always
+// hit and critical fails always miss. Returns an AttackResolution array and an error array for invalid RollArgs
+// and expressions without attack roll. In strict mode, returns no AttackResolution if anything is invalid.
+func (roller *Roller) ResolveAttacks(ac int, rollArgs ...string) (resolutions []AttackResolution, errs []error) {
+	rollExprs, errs := parseRollArgs(rollArgs...)
+	if roller.strict() {
+		if errs = append(errs, validateRollingExpressions(rollExprs...)...); len(errs) > 0 {
+			return nil, errs
+		}
+	}
+
+	for e := 0; e < len(rollExprs); e++ {
+		if rollExprs[e].kind != attackExpression {
+			if !rollExprs[e].isEmpty() {
+				errs = append(errs, fmt.Errorf("rolling expression %d has no attack roll", e+1))
+			}
+			continue
+		}
+
+		hit, diceErrs := roller.performRollingExpression(rollExprs[e], false)
+		errs = append(errs, diceErrs...)
+		resolution := AttackResolution{AttackResult{hit, []RollResult{}}, ac, attackOutcomeOf(*hit, ac)}
+
+		// Damage owned by the attack
+		for ; e+1 < len(rollExprs) && rollExprs[e+1].kind == damageExpression; e++ {
+			if resolution.Hit() {
+				damage, diceErrs := roller.performRollingExpression(rollExprs[e+1], resolution.outcome == AttackCritHit)
+				errs = append(errs, diceErrs...)
+				resolution.attack.damage = append(resolution.attack.damage, *damage)
+			}
+		}
+
+		resolutions = append(resolutions, resolution)
+	}
+
+	if roller.strict() && len(errs) > 0 {
+		return nil, errs
+	}
+	return resolutions, errs
+}
+
+// Returns the AttackOutcome of the attack roll hit against the Armor Class ac.
+func attackOutcomeOf(hit RollResult, ac int) AttackOutcome {
+	outcome := AttackMiss
+	switch {
+	case hit.CritHit():
+		outcome = AttackCritHit
+	case hit.CritFail():
+		outcome = AttackCritMiss
+	case hit.Sum() >= ac:
+		outcome = AttackHit
+	}
+	return outcome
+}
+
+// Returns the AttackOutcome of the attack.
+func (resolution AttackResolution) Outcome() AttackOutcome {
+	return resolution.outcome
+}
+
+// Returns true if the attack hit, critical hits included.
+func (resolution AttackResolution) Hit() bool {
+	return resolution.outcome == AttackHit || resolution.outcome == AttackCritHit
+}
+
+// Returns the attack roll sum.
+func (resolution AttackResolution) HitSum() int {
+	return resolution.attack.HitSum()
+}
+
+// Returns the damage dealt, 0 on misses.
+func (resolution AttackResolution) DamageSum() int {
+	return resolution.attack.DamageSum()
+}
+
+// Human readable AttackOutcome string.
+func (outcome AttackOutcome) String() string {
+	return attackOutcomeStrs[outcome]
+}
+
+// Human readable AttackResolution string.
+func (resolution AttackResolution) String() string {
+	resolutionStr := resolution.attack.String()
+	resolutionStr += fmt.Sprintf("Attack roll %d against AC %d: %s \n", resolution.HitSum(), resolution.ac, resolution.outcome)
+	return resolutionStr
+}
+
+// Returns the attack roll and damage results, with their per-die breakdown.
+func (resolution AttackResolution) Attack() AttackResult {
+	return resolution.attack
+}
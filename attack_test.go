@@ -0,0 +1,86 @@
+package diceroller
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPerformAttacks(t *testing.T) {
+	// Max source rolls natural 20s, the second attack only crits on a 1
+	roller := NewRoller(maxSource{})
+	attacks, attackErrs := roller.PerformAttacks("hit", "1d20+7", "dmg", "1d8+4", "dmg", "1d6", "hit", "1d20cs1+7", "dmg", "1d8+4", "roll", "1d8")
+	if len(attackErrs) > 0 {
+		t.Fatalf("Unexpected attack errors: %v", attackErrs)
+	}
+	if len(attacks) != 3 {
+		t.Fatalf("Attacks grouped into %d attackResults, wanted 3", len(attacks))
+	}
+
+	if !attacks[0].CritHit() || attacks[0].HitSum() != 27 || len(attacks[0].damage) != 2 || attacks[0].DamageSum() != 32 {
+		t.Fatalf("Critical attack hit %d for %d damage:\n%s", attacks[0].HitSum(), attacks[0].DamageSum(), attacks[0])
+	}
+	if attacks[1].CritHit() || len(attacks[1].damage) != 1 || attacks[1].DamageSum() != 12 {
+		t.Fatalf("Critical hit applied to another attack damage:\n%s", attacks[1])
+	}
+	if attacks[2].hit != nil || attacks[2].DamageSum() != 8 {
+		t.Fatalf("Plain expression grouped as an attack or got a critical hit:\n%s", attacks[2])
+	}
+}
+
+func TestDamageWithoutAttack(t *testing.T) {
+	attacks, _ := NewRoller(maxSource{}).PerformAttacks("roll", "1d20", "dmg", "8d6", "dmg", "1d6")
+	if len(attacks) != 2 || attacks[1].hit != nil || len(attacks[1].damage) != 2 || attacks[1].DamageSum() != 54 {
+		t.Fatalf("Damage without attack roll grouped into %d attackResults:\n%v", len(attacks), attacks)
+	}
+}
+
+func TestAttackDistribution(t *testing.T) {
+	wantedMeans := []struct {
+		rollArgs []string
+		mean     float64
+	}{
+		{[]string{"hit", "1d20", "dmg", "1d6", "roll", "1d6"}, 14.175 + 3.5},
+		{[]string{"hit", "1d20", "dmg", "1d6", "dmg", "1d4"}, 14.175 + 2.5*0.95 + 5*0.05},
+		{[]string{"roll", "1d20", "dmg", "1d6"}, 14},
+	}
+	for i := range wantedMeans {
+		distribution, _ := RollArgsDistribution(wantedMeans[i].rollArgs...)
+		if math.Abs(distribution.Mean()-wantedMeans[i].mean) > 1e-9 {
+			t.Fatalf("Roll Args %v mean is %f, expected %f", wantedMeans[i].rollArgs, distribution.Mean(), wantedMeans[i].mean)
+		}
+	}
+}
+
+func TestResolveAttacks(t *testing.T) {
+	// Max source rolls natural 20s, critical ranges shape each outcome
+	roller := NewRoller(maxSource{})
+	resolutions, resolveErrs := roller.ResolveAttacks(25,
+		"hit", "1d20+1", "dmg", "1d8+4",
+		"hit", "1d20cs1+5", "dmg", "1d8+4",
+		"hit", "1d20cs1", "dmg", "1d8+4",
+		"hit", "1d20cs1cf>=20+10", "dmg", "1d8+4", "dmg", "1d6")
+	if len(resolveErrs) > 0 {
+		t.Fatalf("Unexpected attack errors: %v", resolveErrs)
+	}
+
+	wantedOutcomes := []struct {
+		outcome AttackOutcome
+		damage  int
+	}{{AttackCritHit, 20}, {AttackHit, 12}, {AttackMiss, 0}, {AttackCritMiss, 0}}
+	if len(resolutions) != len(wantedOutcomes) {
+		t.Fatalf("Resolved %d attacks, wanted %d", len(resolutions), len(wantedOutcomes))
+	}
+	for i := range wantedOutcomes {
+		if resolutions[i].Outcome() != wantedOutcomes[i].outcome || resolutions[i].DamageSum() != wantedOutcomes[i].damage {
+			t.Fatalf("Attack %d outcome %s for %d damage, wanted %s for %d", i+1, resolutions[i].Outcome(),
+				resolutions[i].DamageSum(), wantedOutcomes[i].outcome, wantedOutcomes[i].damage)
+		}
+		if !resolutions[i].Hit() && len(resolutions[i].Attack().damage) > 0 {
+			t.Fatalf("Attack %d missed but rolled damage", i+1)
+		}
+	}
+
+	if _, resolveErrs := ResolveAttacks(15, "dmg", "1d8"); len(resolveErrs) == 0 {
+		t.Fatal("Damage without attack roll returned no error")
+	}
+}
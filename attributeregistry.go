@@ -0,0 +1,193 @@
+package diceroller
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sync"
+
+	"golang.org/x/exp/maps"
+)
+
+// Hooks of a custom RollAttribute, run at defined points of each roll of the DiceRolls it is set on.
+// Nil hooks do nothing. Hooks of several custom RollAttributes run in registration order.
+type RollAttributeHooks struct {
+	BeforeRoll    func(diceAmmount int, diceSize int) (int, int) // Changes the ammount and size of the dice rolled
+	PerDie        func(roll int, diceSize int) int               // Changes each die roll, after rerolls, advantage and explosions
+	AfterDrops    func(dice []int, sum int) int                  // Changes the dice sum, after keep and drop rules
+	AfterModifier func(sum int) int                              // Changes the sum, after adding the modifier
+}
+
+// Definition of a custom RollAttribute.
+type RollAttributeDef struct {
+	Keywords []string           // RollArgs setting the RollAttribute, such as "bless". The shortest is its String
+	Excludes []RollAttribute    // RollAttributes it can't be set with, setting either one unsets the other
+	Hooks    RollAttributeHooks // Behaviour of the RollAttribute
+}
+
+// Registry of the RollAttributes, guarding rollAttributeMap and rollAttributeExclusions.
+var attributeRegistry = struct {
+	sync.RWMutex
+	next  RollAttribute                        // Next custom RollAttribute value
+	hooks map[RollAttribute]RollAttributeHooks // Hooks of custom RollAttributes
+}{next: MinusAttrib + 1, hooks: make(map[RollAttribute]RollAttributeHooks)}
+
+// RollAttributes unset when setting the key RollAttribute.
+var rollAttributeExclusions = map[RollAttribute][]RollAttribute{
+	AdvantageAttrib:    {DisadvantageAttrib},
+	DisadvantageAttrib: {AdvantageAttrib},
+}
+
+// Registers a custom RollAttribute, set by its keywords as RollArgs like built-in ones. Returns the new
+// RollAttribute if valid, an error if invalid or if a keyword is already taken.
+func RegisterRollAttribute(def RollAttributeDef) (RollAttribute, error) {
+	attributeRegistry.Lock()
+	defer attributeRegistry.Unlock()
+
+	if len(def.Keywords) == 0 {
+		return 0, fmt.Errorf("invalid RollAttribute definition: no keyword")
+	}
+	for i := range def.Keywords {
+		if keywordErr := validateAttributeKeyword(def.Keywords[i], def.Keywords[:i]); keywordErr != nil {
+			return 0, keywordErr
+		}
+	}
+	for i := range def.Excludes {
+		if def.Excludes[i] < RollAttrib || def.Excludes[i] >= attributeRegistry.next {
+			return 0, fmt.Errorf("invalid RollAttribute definition: unknown excluded RollAttribute %d", def.Excludes[i])
+		}
+	}
+
+	rollAttrib := attributeRegistry.next
+	attributeRegistry.next++
+	for i := range def.Keywords {
+		rollAttributeMap[def.Keywords[i]] = rollAttrib
+	}
+	for i := range def.Excludes {
+		rollAttributeExclusions[rollAttrib] = append(rollAttributeExclusions[rollAttrib], def.Excludes[i])
+		rollAttributeExclusions[def.Excludes[i]] = append(rollAttributeExclusions[def.Excludes[i]], rollAttrib)
+	}
+	attributeRegistry.hooks[rollAttrib] = def.Hooks
+
+	return rollAttrib, nil
+}
+
+// Returns the RollAttribute set by keyword. Returns false if keyword is unknown.
+func LookupRollAttribute(keyword string) (RollAttribute, bool) {
+	rollAttrib := lookupRollAttribute(keyword)
+	return rollAttrib, rollAttrib != 0
+}
+
+// Validates a custom RollAttribute keyword, unique among previous keywords. Returns nil if valid, an error if invalid.
+func validateAttributeKeyword(keyword string, previous []string) error {
+	if !regexp.MustCompile(rollAttribsFormat).MatchString(keyword) {
+		return fmt.Errorf("invalid RollAttribute keyword %q, lowercase letters only", keyword)
+	}
+	_, isAttrib := rollAttributeMap[keyword]
+	_, isAlias := keepDropAliasMap[keyword]
+	if isAttrib || isAlias || checkForDamageType(keyword) != DamageUntyped || slices.Contains(previous, keyword) {
+		return fmt.Errorf("invalid RollAttribute keyword %q, already taken", keyword)
+	}
+	return nil
+}
+
+// Returns the RollAttribute matching rollArg, zero if none.
+func lookupRollAttribute(rollArg string) RollAttribute {
+	attributeRegistry.RLock()
+	defer attributeRegistry.RUnlock()
+	return rollAttributeMap[rollArg]
+}
+
+// Returns every RollAttribute keyword.
+func rollAttributeKeywords() []string {
+	attributeRegistry.RLock()
+	defer attributeRegistry.RUnlock()
+	return maps.Keys(rollAttributeMap)
+}
+
+// Returns true if rollAttrib is built-in or registered.
+func isRollAttribute(rollAttrib RollAttribute) bool {
+	attributeRegistry.RLock()
+	defer attributeRegistry.RUnlock()
+	return rollAttrib >= RollAttrib && rollAttrib < attributeRegistry.next
+}
+
+// Returns the RollAttributes unset when setting rollAttrib.
+func rollAttributeExcludes(rollAttrib RollAttribute) []RollAttribute {
+	attributeRegistry.RLock()
+	defer attributeRegistry.RUnlock()
+	return rollAttributeExclusions[rollAttrib]
+}
+
+// Returns the hooks of the custom RollAttributes set on the DiceRoll, in registration order.
+func (diceRoll DiceRoll) attributeHooks() []RollAttributeHooks {
+	var hooks []RollAttributeHooks
+	if diceRoll.rollAttribs == nil {
+		return hooks
+	}
+
+	attributeRegistry.RLock()
+	defer attributeRegistry.RUnlock()
+	rollAttribs := maps.Keys(diceRoll.rollAttribs.attribs)
+	slices.Sort(rollAttribs)
+	for _, rollAttrib := range rollAttribs {
+		if attribHooks, found := attributeRegistry.hooks[rollAttrib]; found {
+			hooks = append(hooks, attribHooks)
+		}
+	}
+	return hooks
+}
+
+// Returns the ammount and size of dice rolled by diceRoll, once changed by BeforeRoll hooks.
+func (diceRoll DiceRoll) hookedDice(hooks []RollAttributeHooks) (diceAmmount int, diceSize int) {
+	diceAmmount, diceSize = diceRoll.diceAmmount, diceRoll.diceSize
+	for i := range hooks {
+		if hooks[i].BeforeRoll != nil {
+			diceAmmount, diceSize = hooks[i].BeforeRoll(diceAmmount, diceSize)
+		}
+	}
+	return diceAmmount, diceSize
+}
+
+// Validates the dice rolled by diceRoll once changed by BeforeRoll hooks. Returns nil if valid, an error if invalid.
+func validateAttributeHooks(diceRoll DiceRoll) error {
+	hooks := diceRoll.attributeHooks()
+	if len(hooks) == 0 {
+		return nil
+	}
+	diceAmmount, diceSize := diceRoll.hookedDice(hooks)
+	if ammountErr := validateDiceAmmout(diceAmmount); ammountErr != nil {
+		return ammountErr
+	}
+	return validateDiceSize(diceSize)
+}
+
+// Applies PerDie hooks to roll. Returns the roll to keep.
+func applyPerDieHooks(hooks []RollAttributeHooks, roll int, diceSize int) int {
+	for i := range hooks {
+		if hooks[i].PerDie != nil {
+			roll = hooks[i].PerDie(roll, diceSize)
+		}
+	}
+	return roll
+}
+
+// Applies AfterDrops hooks to the sum of the kept dice. Returns the new sum.
+func applyAfterDropsHooks(hooks []RollAttributeHooks, dice []int, sum int) int {
+	for i := range hooks {
+		if hooks[i].AfterDrops != nil {
+			sum = hooks[i].AfterDrops(slices.Clone(dice), sum)
+		}
+	}
+	return sum
+}
+
+// Applies AfterModifier hooks to sum. Returns the new sum.
+func applyAfterModifierHooks(hooks []RollAttributeHooks, sum int) int {
+	for i := range hooks {
+		if hooks[i].AfterModifier != nil {
+			sum = hooks[i].AfterModifier(sum)
+		}
+	}
+	return sum
+}
@@ -0,0 +1,155 @@
+package diceroller
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// Custom RollAttributes registered once for every test
+var (
+	brutalAttrib = mustRegisterRollAttribute(RollAttributeDef{[]string{"brutal"}, nil,
+		RollAttributeHooks{BeforeRoll: func(diceAmmount int, diceSize int) (int, int) { return diceAmmount + 1, diceSize }}})
+	gwfAttrib = mustRegisterRollAttribute(RollAttributeDef{[]string{"gwf", "greatweapon"}, []RollAttribute{brutalAttrib},
+		RollAttributeHooks{PerDie: func(roll int, diceSize int) int { return max(roll, 3) }}})
+	doubledAttrib = mustRegisterRollAttribute(RollAttributeDef{[]string{"doubled"}, nil,
+		RollAttributeHooks{AfterDrops: func(dice []int, sum int) int { return sum * 2 }}})
+	blessedAttrib = mustRegisterRollAttribute(RollAttributeDef{[]string{"blessed"}, []RollAttribute{HalfAttrib},
+		RollAttributeHooks{AfterModifier: func(sum int) int { return sum + 10 }}})
+	shrinkAttrib = mustRegisterRollAttribute(RollAttributeDef{[]string{"shrink"}, nil,
+		RollAttributeHooks{BeforeRoll: shrinkD3}})
+)
+
+// Shrinks d3 to invalid d1.
+func shrinkD3(diceAmmount int, diceSize int) (int, int) {
+	if diceSize == 3 {
+		diceSize = 1
+	}
+	return diceAmmount, diceSize
+}
+
+func mustRegisterRollAttribute(def RollAttributeDef) RollAttribute {
+	rollAttrib, registerErr := RegisterRollAttribute(def)
+	if registerErr != nil {
+		panic(registerErr)
+	}
+	return rollAttrib
+}
+
+func TestCustomRollAttributeHooks(t *testing.T) {
+	// Sums with every die rolling its lowest face, then its highest face
+	wantedSums := []struct {
+		rollArgs []string
+		lowest   int
+		highest  int
+	}{
+		{[]string{"brutal", "2d6+1"}, 4, 19},
+		{[]string{"gwf", "2d6"}, 6, 12},
+		{[]string{"greatweapon", "4d6dl1"}, 9, 18},
+		{[]string{"doubled", "2d6kh1+1"}, 3, 13},
+		{[]string{"blessed", "1d8-4"}, 7, 14},
+		{[]string{"brutal", "gwf", "doubled", "1d6"}, 6, 12},
+	}
+
+	for i := range wantedSums {
+		if sum := NewRoller(minSource{}).PerformRollArgsAndSum(wantedSums[i].rollArgs...); sum != wantedSums[i].lowest {
+			t.Fatalf("%v lowest sum is %d, expected %d", wantedSums[i].rollArgs, sum, wantedSums[i].lowest)
+		}
+		if sum := NewRoller(maxSource{}).PerformRollArgsAndSum(wantedSums[i].rollArgs...); sum != wantedSums[i].highest {
+			t.Fatalf("%v highest sum is %d, expected %d", wantedSums[i].rollArgs, sum, wantedSums[i].highest)
+		}
+	}
+
+	// Results keep the DiceRoll as parsed
+	results, _ := NewRoller(maxSource{}).PerformRollArgs("brutal", "2d6")
+	if result := results[0].results[0]; len(result.dice) != 3 || result.diceRoll.diceAmmount != 2 {
+		t.Fatalf("Brutal 2d6 rolled %v for DiceRoll %s, expected 3 dice for 2d6", result.dice, result.diceRoll)
+	}
+}
+
+func TestCustomRollAttributeExclusions(t *testing.T) {
+	diceRoll, _ := ParseDiceRoll("brutal gwf 1d6")
+	if diceRoll.hasAttrib(brutalAttrib) || !diceRoll.hasAttrib(gwfAttrib) {
+		t.Fatalf("Excluded brutal is set in %s", diceRoll)
+	}
+	diceRoll, _ = ParseDiceRoll("blessed half 1d6")
+	if diceRoll.hasAttrib(blessedAttrib) || !diceRoll.hasAttrib(HalfAttrib) {
+		t.Fatalf("Excluded blessed is set in %s", diceRoll)
+	}
+
+	// Built-in exclusions still apply
+	diceRoll, _ = ParseDiceRoll("adv dis 1d20")
+	if diceRoll.hasAttrib(AdvantageAttrib) || !diceRoll.hasAttrib(DisadvantageAttrib) {
+		t.Fatalf("Advantage is set in %s", diceRoll)
+	}
+}
+
+func TestCustomRollAttributeSupport(t *testing.T) {
+	if rollAttrib, found := LookupRollAttribute("greatweapon"); !found || rollAttrib != gwfAttrib {
+		t.Fatalf("Lookup of greatweapon returned %d, expected %d", rollAttrib, gwfAttrib)
+	}
+	if gwfAttrib.String() != "gwf" {
+		t.Fatalf("Custom RollAttribute string is %s, expected gwf", gwfAttrib)
+	}
+
+	// Printing, parsing, building and encoding
+	diceRoll, buildErr := D(6).Times(2).With(gwfAttrib, doubledAttrib).Build()
+	if buildErr != nil || diceRoll.String() != "doubled gwf 2d6" {
+		t.Fatalf("Built custom DiceRoll %s with error %v, expected doubled gwf 2d6", diceRoll, buildErr)
+	}
+	if parsed, parseErr := ParseDiceRoll(diceRoll.String()); parseErr != nil || parsed.String() != diceRoll.String() {
+		t.Fatalf("Parsed custom DiceRoll %s with error %v, expected %s", parsed, parseErr, diceRoll)
+	}
+	encoded, _ := json.Marshal(diceRoll)
+	decoded := DiceRoll{}
+	if decodeErr := json.Unmarshal(encoded, &decoded); decodeErr != nil || decoded.String() != diceRoll.String() {
+		t.Fatalf("Decoded custom DiceRoll %s with error %v, expected %s", decoded, decodeErr, diceRoll)
+	}
+
+	// Suggestions
+	_, errs := PerformRollArgs("brutl", "1d6")
+	var parseErr *ParseError
+	if len(errs) != 1 || !errors.As(errs[0], &parseErr) || parseErr.Suggestion != "brutal" {
+		t.Fatalf("Misspelled brutal returned errors %v, expected a brutal suggestion", errs)
+	}
+
+	// Hooks can't be computed exactly
+	if _, distErr := RollArgsDistribution("gwf", "2d6"); distErr == nil {
+		t.Fatalf("Distribution of custom RollAttribute hooks returned no error")
+	}
+}
+
+func TestInvalidCustomRollAttributes(t *testing.T) {
+	invalidDefs := []RollAttributeDef{
+		{nil, nil, RollAttributeHooks{}},
+		{[]string{"Bless"}, nil, RollAttributeHooks{}},
+		{[]string{"bless2"}, nil, RollAttributeHooks{}},
+		{[]string{"adv"}, nil, RollAttributeHooks{}},
+		{[]string{"fire"}, nil, RollAttributeHooks{}},
+		{[]string{"droplow"}, nil, RollAttributeHooks{}},
+		{[]string{"gwf"}, nil, RollAttributeHooks{}},
+		{[]string{"bless", "bless"}, nil, RollAttributeHooks{}},
+		{[]string{"bless"}, []RollAttribute{0}, RollAttributeHooks{}},
+		{[]string{"bless"}, []RollAttribute{shrinkAttrib + 100}, RollAttributeHooks{}},
+	}
+	for i := range invalidDefs {
+		if rollAttrib, registerErr := RegisterRollAttribute(invalidDefs[i]); registerErr == nil {
+			t.Fatalf("Invalid RollAttribute definition %v registered as %d", invalidDefs[i], rollAttrib)
+		}
+	}
+	if _, found := LookupRollAttribute("bless"); found {
+		t.Fatalf("Invalid RollAttribute definition registered its keyword")
+	}
+
+	// Hooks changing the dice to invalid values
+	_, errs := PerformRollArgs("shrink", "1d3")
+	if len(errs) != 1 || !errors.Is(errs[0], BadAttribute) {
+		t.Fatalf("Shrinking 1d3 returned errors %v, expected a BadAttribute error", errs)
+	}
+	if _, buildErr := D(3).With(shrinkAttrib).Build(); !errors.Is(buildErr, BadAttribute) {
+		t.Fatalf("Building shrunk 1d3 returned error %v, expected a BadAttribute error", buildErr)
+	}
+	if _, buildErr := D(4).With(shrinkAttrib + 100).Build(); buildErr == nil {
+		t.Fatalf("Building with an unknown RollAttribute returned no error")
+	}
+}
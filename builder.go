@@ -0,0 +1,183 @@
+package diceroller
+
+import (
+	"fmt"
+)
+
+// A DiceRollBuilder builds a DiceRoll step by step, such as D(20).Times(1).Plus(5).Advantage().Build().
+// Thresholds use the RollArg syntax, such as "1", "<3" or ">=5".
+type DiceRollBuilder struct {
+	diceRoll DiceRoll
+	err      error // First invalid step, returned by Build
+}
+
+// Starts building a single diceSize die DiceRoll.
+func D(diceSize int) *DiceRollBuilder {
+	return &DiceRollBuilder{DiceRoll{1, diceSize, 0, newRollAttributes()}, nil}
+}
+
+// Sets the ammount of dice to roll.
+func (builder *DiceRollBuilder) Times(diceAmmount int) *DiceRollBuilder {
+	builder.diceRoll.diceAmmount = diceAmmount
+	return builder
+}
+
+// Adds modifier to the DiceRoll modifier, negative values subtract.
+func (builder *DiceRollBuilder) Plus(modifier int) *DiceRollBuilder {
+	builder.diceRoll.modifier += modifier
+	return builder
+}
+
+// Subtracts the DiceRoll from the total, modifier included.
+func (builder *DiceRollBuilder) Negative() *DiceRollBuilder {
+	return builder.With(MinusAttrib)
+}
+
+// Sets rollAttribs, built-in or registered.
+func (builder *DiceRollBuilder) With(rollAttribs ...RollAttribute) *DiceRollBuilder {
+	for i := range rollAttribs {
+		if !isRollAttribute(rollAttribs[i]) {
+			return builder.fail(fmt.Errorf("invalid RollAttribute %d", rollAttribs[i]))
+		}
+	}
+	builder.diceRoll.rollAttribs.setRollAttrib(rollAttribs...)
+	return builder
+}
+
+// Rolls each die twice and drops the lowest.
+func (builder *DiceRollBuilder) Advantage() *DiceRollBuilder {
+	return builder.With(AdvantageAttrib)
+}
+
+// Rolls each die twice and drops the highest.
+func (builder *DiceRollBuilder) Disadvantage() *DiceRollBuilder {
+	return builder.With(DisadvantageAttrib)
+}
+
+// Applies the Roller critical damage policy.
+func (builder *DiceRollBuilder) Crit() *DiceRollBuilder {
+	return builder.With(CritAttrib)
+}
+
+// Halves the sum.
+func (builder *DiceRollBuilder) Half() *DiceRollBuilder {
+	return builder.With(HalfAttrib)
+}
+
+// Keeps the count highest dice.
+func (builder *DiceRollBuilder) KeepHighest(count int) *DiceRollBuilder {
+	return builder.keepDrop(keepHighOp, count)
+}
+
+// Keeps the count lowest dice.
+func (builder *DiceRollBuilder) KeepLowest(count int) *DiceRollBuilder {
+	return builder.keepDrop(keepLowOp, count)
+}
+
+// Drops the count highest dice.
+func (builder *DiceRollBuilder) DropHighest(count int) *DiceRollBuilder {
+	return builder.keepDrop(dropHighOp, count)
+}
+
+// Drops the count lowest dice.
+func (builder *DiceRollBuilder) DropLowest(count int) *DiceRollBuilder {
+	return builder.keepDrop(dropLowOp, count)
+}
+
+// Rerolls a die as long as it matches threshold.
+func (builder *DiceRollBuilder) Reroll(threshold string) *DiceRollBuilder {
+	return builder.reroll(rerollStr, threshold)
+}
+
+// Rerolls a die matching threshold once.
+func (builder *DiceRollBuilder) RerollOnce(threshold string) *DiceRollBuilder {
+	return builder.reroll(rerollOnceStr, threshold)
+}
+
+// Explodes dice matching threshold, an empty threshold explodes on the highest face.
+func (builder *DiceRollBuilder) Explode(threshold string) *DiceRollBuilder {
+	return builder.explode(explodeStr, threshold)
+}
+
+// Compounds dice matching threshold, an empty threshold compounds on the highest face.
+func (builder *DiceRollBuilder) Compound(threshold string) *DiceRollBuilder {
+	return builder.explode(explodeCompoundStr, threshold)
+}
+
+// Penetrates dice matching threshold, an empty threshold penetrates on the highest face.
+func (builder *DiceRollBuilder) Penetrate(threshold string) *DiceRollBuilder {
+	return builder.explode(explodePenetrateStr, threshold)
+}
+
+// Counts the dice matching target as successes, and those matching failure as failures. An empty failure counts none.
+func (builder *DiceRollBuilder) CountSuccesses(target string, failure string) *DiceRollBuilder {
+	rule, argErr := parseSuccessRule(target, failure)
+	if argErr != nil {
+		return builder.fail(argErr)
+	}
+	builder.diceRoll.rollAttribs.success = rule
+	return builder
+}
+
+// Sets the critical hit and fail ranges, an empty range uses the Roller range.
+func (builder *DiceRollBuilder) CritRange(hit string, fail string) *DiceRollBuilder {
+	crits, argErr := parseCritRange(hit, fail)
+	if argErr != nil {
+		return builder.fail(argErr)
+	}
+	builder.diceRoll.rollAttribs.crits = crits
+	return builder
+}
+
+// Sets the DamageType.
+func (builder *DiceRollBuilder) Damage(damageType DamageType) *DiceRollBuilder {
+	if damageType < DamageUntyped || damageType > DamageThunder {
+		return builder.fail(fmt.Errorf("invalid damage type %d", damageType))
+	}
+	builder.diceRoll.rollAttribs.damageType = damageType
+	return builder
+}
+
+// Validates the built DiceRoll. Returns the DiceRoll if valid, the first error met if invalid.
+// The builder can keep building without affecting returned DiceRolls.
+func (builder *DiceRollBuilder) Build() (*DiceRoll, error) {
+	if builder.err != nil {
+		return nil, builder.err
+	}
+	diceRoll := builder.diceRoll
+	return NewDiceRollWithAttribs(diceRoll.diceAmmount, diceRoll.diceSize, diceRoll.modifier, diceRoll.rollAttribs.clone())
+}
+
+// Records the first invalid step.
+func (builder *DiceRollBuilder) fail(err error) *DiceRollBuilder {
+	if builder.err == nil {
+		builder.err = err
+	}
+	return builder
+}
+
+// Appends a keepDropRule.
+func (builder *DiceRollBuilder) keepDrop(op keepDropOp, count int) *DiceRollBuilder {
+	builder.diceRoll.rollAttribs.keepDrop = append(builder.diceRoll.rollAttribs.keepDrop, keepDropRule{op, count})
+	return builder
+}
+
+// Sets the rerollRule.
+func (builder *DiceRollBuilder) reroll(modeStr string, threshold string) *DiceRollBuilder {
+	rule, argErr := parseRerollRule(modeStr, threshold)
+	if argErr != nil {
+		return builder.fail(argErr)
+	}
+	builder.diceRoll.rollAttribs.reroll = rule
+	return builder
+}
+
+// Sets the explodeRule.
+func (builder *DiceRollBuilder) explode(modeStr string, threshold string) *DiceRollBuilder {
+	rule, argErr := parseExplodeRule(modeStr, threshold)
+	if argErr != nil {
+		return builder.fail(argErr)
+	}
+	builder.diceRoll.rollAttribs.explode = rule
+	return builder
+}
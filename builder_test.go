@@ -0,0 +1,68 @@
+package diceroller
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiceRollBuilder(t *testing.T) {
+	builders := []struct {
+		builder   *DiceRollBuilder
+		diceStr   string
+		wantedSum int
+	}{
+		{D(20).Times(1).Plus(5).Advantage(), "adv 1d20+5", 25},
+		{D(6).Times(4).DropLowest(1), "4d6dl1", 18},
+		{D(6).Times(4).KeepHighest(3).DropHighest(1), "drophigh 4d6kh3", 12},
+		{D(8).Times(2).Plus(3).Crit().Damage(DamageFire), "crit 2d8+3 fire", 35},
+		{D(10).Times(6).Explode(">=10").CountSuccesses(">=8", "1"), "6d10!>=10>=8f1", 606},
+		{D(6).Times(2).Reroll("1").Negative(), "minus -2d6r1", -12},
+		{D(20).CritRange(">=19", "").Disadvantage(), "dis 1d20cs>=19", 20},
+		{D(4).Times(3).Penetrate("").Half(), "half 3d4!p", 456},
+	}
+
+	for i := range builders {
+		diceRoll, diceErr := builders[i].builder.Build()
+		if diceErr != nil {
+			t.Fatalf("Builder %d returned error: %s", i, diceErr)
+		}
+		reparsed, argErr := ParseDiceRoll(builders[i].diceStr)
+		if argErr != nil || !reflect.DeepEqual(reparsed, diceRoll) {
+			t.Fatalf("Built DiceRoll %s, wanted %s", diceRoll, builders[i].diceStr)
+		}
+		if sum := NewRoller(maxSource{}).Roll(*diceRoll); sum != builders[i].wantedSum {
+			t.Fatalf("Built DiceRoll %s rolled %d, wanted %d", diceRoll, sum, builders[i].wantedSum)
+		}
+	}
+}
+
+func TestInvalidDiceRollBuilder(t *testing.T) {
+	builders := []*DiceRollBuilder{
+		D(1),
+		D(6).Times(0),
+		D(6).Plus(500000),
+		D(6).With(RollAttribute(0)),
+		D(6).Reroll(">=1"),
+		D(6).Explode("x"),
+		D(6).Times(4).KeepHighest(3).KeepLowest(2),
+		D(10).Times(6).Explode("").CountSuccesses(">=8", ""),
+		D(8).CritRange(">=19", ""),
+		D(6).Damage(DamageType(99)),
+	}
+
+	for i := range builders {
+		if diceRoll, diceErr := builders[i].Build(); diceErr == nil {
+			t.Fatalf("Invalid builder %d returned DiceRoll %s", i, diceRoll)
+		}
+	}
+}
+
+func TestDiceRollBuilderReuse(t *testing.T) {
+	builder := D(20).Plus(2)
+	first, _ := builder.Build()
+	second, _ := builder.Advantage().DropLowest(1).Build()
+
+	if first.String() != "1d20+2" || second.String() != "adv 1d20dl1+2" {
+		t.Fatalf("Reused builder built %s and %s", first, second)
+	}
+}
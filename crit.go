@@ -0,0 +1,135 @@
+package diceroller
+
+import (
+	"fmt"
+)
+
+// Allowed critical range strings in a RollArg.
+const (
+	critSuccessStr string = "cs"
+	critFailStr    string = "cf"
+)
+
+// Size of the dice scoring critical hits and fumbles.
+const critDiceSize int = 20
+
+// A critRange holds the natural d20 rolls scoring critical hits and critical fails.
+type critRange struct {
+	hit  *rollThreshold // Rolls scoring a critical hit, nil to use the Roller range
+	fail *rollThreshold // Rolls scoring a critical fail, nil to use the Roller range
+}
+
+// Default critical range, a natural 20 is a critical hit and a natural 1 a critical fail.
+var defaultCritRange = critRange{&rollThreshold{compareGreaterEqual, 20}, &rollThreshold{compareLessEqual, 1}}
+
+// Parses critical range RollArg slices such as ">=19" and "1". Returns a critRange if valid, an error if invalid.
+func parseCritRange(hitStr string, failStr string) (*critRange, error) {
+	crits := &critRange{}
+	if len(hitStr) > 0 {
+		hit, argErr := parseRollThreshold(hitStr)
+		if argErr != nil {
+			return nil, argErr
+		}
+		crits.hit = hit
+	}
+	if len(failStr) > 0 {
+		fail, argErr := parseRollThreshold(failStr)
+		if argErr != nil {
+			return nil, argErr
+		}
+		crits.fail = fail
+	}
+	return crits, nil
+}
+
+// Critical range string, such as "cs>=19cf1".
+func (crits critRange) String() string {
+	critStr := ""
+	if crits.hit != nil {
+		critStr += critSuccessStr + crits.hit.String()
+	}
+	if crits.fail != nil {
+		critStr += critFailStr + crits.fail.String()
+	}
+	return critStr
+}
+
+// Returns the critRange with the unset thresholds taken from defaults.
+func (crits critRange) withDefaults(defaults critRange) critRange {
+	if crits.hit == nil {
+		crits.hit = defaults.hit
+	}
+	if crits.fail == nil {
+		crits.fail = defaults.fail
+	}
+	return crits
+}
+
+// Returns true if roll scores a critical hit.
+func (crits critRange) isCritHit(roll int) bool {
+	return crits.hit != nil && crits.hit.matches(roll)
+}
+
+// Returns true if roll scores a critical fail. Critical hits take precedence.
+func (crits critRange) isCritFail(roll int) bool {
+	return !crits.isCritHit(roll) && crits.fail != nil && crits.fail.matches(roll)
+}
+
+// Validates a critRange. Returns nil if valid, an error if invalid.
+func validateCritRange(crits *critRange, diceSize int) error {
+	if crits == nil {
+		return nil
+	}
+	if diceSize != critDiceSize {
+		return fmt.Errorf("critical range set on a d%d, only d%d score critical hits", diceSize, critDiceSize)
+	}
+	if crits.hit != nil && crits.hit.matchingFaces(diceSize) == 0 {
+		return fmt.Errorf("critical hit range %s matches no face", crits.hit)
+	}
+	return nil
+}
+
+// Sets the natural d20 rolls scoring critical hits and critical fails: rolls of critHit or more are critical hits,
+// rolls of critFail or less are critical fails. A critFail of 0 disables critical fails. DiceRoll critical ranges
+// take precedence. Returns an error if the range is invalid.
+func (roller *Roller) SetCritRange(critHit int, critFail int) error {
+	if critHit < 2 || critHit > critDiceSize {
+		return fmt.Errorf("critical hit range must be between 2 and %d, got %d", critDiceSize, critHit)
+	}
+	if critFail < 0 || critFail >= critHit {
+		return fmt.Errorf("critical fail range must be between 0 and %d, got %d", critHit-1, critFail)
+	}
+
+	roller.crits = critRange{&rollThreshold{compareGreaterEqual, critHit}, nil}
+	if critFail > 0 {
+		roller.crits.fail = &rollThreshold{compareLessEqual, critFail}
+	}
+	return nil
+}
+
+// Returns the critRange of diceRoll, Roller range included.
+func (roller *Roller) critRangeOf(diceRoll DiceRoll) critRange {
+	if crits := diceRoll.critRange(); crits != nil {
+		return crits.withDefaults(roller.crits)
+	}
+	return roller.crits
+}
+
+// Detects critical hits and critical fails on a single kept d20 of a performed RollState. Advantage, disadvantage,
+// explosions and modifiers don't matter, the natural kept roll does. Success counting dice pools never score critical hits.
+func (roller *Roller) detectCrits(state *RollState) {
+	diceRollResult := state.result
+	diceRoll := diceRollResult.diceRoll
+	if len(diceRollResult.dice) != 1 || diceRoll.diceSize != critDiceSize || diceRoll.successRule() != nil {
+		return
+	}
+
+	natural := diceRollResult.dice[0]
+	if len(state.rolled) == 1 {
+		natural = state.rolled[0].natural
+	}
+
+	crits := roller.critRangeOf(diceRoll)
+	diceRollResult.critHit = crits.isCritHit(natural)
+	diceRollResult.critFail = crits.isCritFail(natural)
+}
@@ -0,0 +1,111 @@
+package diceroller
+
+import (
+	"math"
+	"testing"
+)
+
+// Valid critical range Roll Args
+var validCritRollArgs = []string{
+	"1d20cs>=19",
+	"1d20cf<=2+5",
+	"1d20cs>=18cf1",
+	"2d20kh1cs>=19-1"}
+
+// Invalid critical range Roll Args
+var invalidCritRollArgs = []string{
+	"1d20cs",
+	"1d20cs>=19cs20",
+	"1d20cf1cs20",
+	"1d6cs6",
+	"1d20cs>20"}
+
+func TestParseValidCritRollArgs(t *testing.T) {
+	for i := range validCritRollArgs {
+		diceRoll, argErr := parseRollArg(validCritRollArgs[i])
+		if argErr != nil {
+			validArgParsingError(argErr, t)
+		}
+		if validateDiceRoll(*diceRoll) != nil || diceRoll.critRange() == nil {
+			t.Fatalf("RollArg %s parsed without a valid critRange", validCritRollArgs[i])
+		}
+		if reparsed, _ := parseRollArg(diceRoll.String()); reparsed == nil || reparsed.String() != diceRoll.String() {
+			t.Fatalf("DiceRoll %s string is not a valid RollArg", diceRoll)
+		}
+	}
+}
+
+func TestParseInvalidCritRollArgs(t *testing.T) {
+	for i := range invalidCritRollArgs {
+		if diceRoll, argErr := parseRollArg(invalidCritRollArgs[i]); argErr == nil && validateDiceRoll(*diceRoll) == nil {
+			invalidArgParsingError(invalidCritRollArgs[i], t)
+		}
+	}
+}
+
+func TestDetectCrits(t *testing.T) {
+	// Max source rolls natural 20s
+	roller := NewRoller(maxSource{})
+	results, _ := roller.PerformRollArgs("hit", "adv", "1d20+5", "dmg", "1d20", "1d8")
+	if !results[0].CritHit() || results[0].CritFail() {
+		t.Fatal("Natural 20 with advantage and modifier didn't score a critical hit")
+	}
+	if results[1].CritHit() {
+		t.Fatal("Crit damage DiceRolls scored a critical hit")
+	}
+
+	results, _ = roller.PerformRollArgs("1d20cs1cf>=20", "2d20kh1", "4d20>=10")
+	if results[0].results[0].CritHit() || !results[0].results[0].CritFail() {
+		t.Fatal("DiceRoll critical range was ignored")
+	}
+	if !results[0].results[1].CritHit() {
+		t.Fatal("Single kept d20 didn't score a critical hit")
+	}
+	if results[0].results[2].CritHit() {
+		t.Fatal("Success counting dice pool scored a critical hit")
+	}
+
+	// Min source rolls natural 1s, exploding on 1 compounds them into a high die
+	results, _ = NewRoller(minSource{}).PerformRollArgs("1d20!!1", "1d20!<=1")
+	for i := range results[0].results {
+		if result := results[0].results[i]; result.CritHit() || !result.CritFail() {
+			t.Fatalf("%s exploding natural 1 scored critical hit %t and critical fail %t", result.diceRoll, result.CritHit(), result.CritFail())
+		}
+	}
+}
+
+func TestSetCritRange(t *testing.T) {
+	roller := NewPCGRoller(1, 2)
+	if roller.SetCritRange(21, 1) == nil || roller.SetCritRange(19, 19) == nil || roller.SetCritRange(19, -1) == nil {
+		t.Fatal("Invalid critical range returned no error")
+	}
+	if rangeErr := roller.SetCritRange(19, 2); rangeErr != nil {
+		t.Fatalf("Valid critical range returned an error: %s", rangeErr.Error())
+	}
+
+	for i := 0; i < 1000; i++ {
+		results, _ := roller.PerformRollArgs("1d20")
+		roll := results[0].results[0].dice[0]
+		if results[0].CritHit() != (roll >= 19) || results[0].CritFail() != (roll <= 2) {
+			t.Fatalf("Roll %d critical hit %t, critical fail %t", roll, results[0].CritHit(), results[0].CritFail())
+		}
+	}
+}
+
+func TestCritRangeDistribution(t *testing.T) {
+	// Critical hits double the damage dice
+	wantedMeans := []struct {
+		rollArgs []string
+		mean     float64
+	}{
+		{[]string{"hit", "1d20cs>=19", "dmg", "1d6"}, 10.5 + 3.5*0.9 + 7*0.1},
+		{[]string{"hit", "adv", "1d20", "dmg", "1d6"}, 13.825 + 3.5*0.9025 + 7*0.0975},
+		{[]string{"hit", "2d20kh1", "dmg", "1d6"}, 13.825 + 3.5*0.9025 + 7*0.0975},
+	}
+	for i := range wantedMeans {
+		distribution, _ := RollArgsDistribution(wantedMeans[i].rollArgs...)
+		if math.Abs(distribution.Mean()-wantedMeans[i].mean) > 1e-9 {
+			t.Fatalf("Roll Args %v mean is %f, expected %f", wantedMeans[i].rollArgs, distribution.Mean(), wantedMeans[i].mean)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package diceroller
+
+import (
+	"fmt"
+)
+
+// A CritDamagePolicy selects how critical DiceRolls increase their result.
+type CritDamagePolicy int
+
+// CritDamagePolicy values. 0 is invalid.
+const (
+	CritDoubleDice  CritDamagePolicy = iota + 1 // Rolls twice the dice, the default
+	CritMaxPlusRoll CritDamagePolicy = iota + 1 // Adds the max roll of the dice to the rolled dice
+	CritDoubleTotal CritDamagePolicy = iota + 1 // Doubles the result, modifier included
+)
+
+// Max allowed extra critical dice, such as Brutal Critical dice.
+const maxCritExtraDice int = 10
+
+// A critDamageRule holds the critical damage settings of a Roller.
+type critDamageRule struct {
+	policy    CritDamagePolicy
+	extraDice int // Extra dice rolled by critical DiceRolls, such as Brutal Critical or Savage Attacks dice
+}
+
+// Default critical damage, rolling twice the dice.
+var defaultCritDamage = critDamageRule{CritDoubleDice, 0}
+
+// Sets how critical DiceRolls, either with the crit rollAttribute or following a critical hit, increase their result.
+// Each critical DiceRoll also rolls extraDice more dice, such as Brutal Critical dice. Returns an error if invalid.
+func (roller *Roller) SetCritDamagePolicy(policy CritDamagePolicy, extraDice int) error {
+	if policy < CritDoubleDice || policy > CritDoubleTotal {
+		return fmt.Errorf("invalid critical damage policy %d", policy)
+	}
+	if extraDice < 0 || extraDice > maxCritExtraDice {
+		return fmt.Errorf("extra critical dice must be between 0 and %d, got %d", maxCritExtraDice, extraDice)
+	}
+	roller.critDamage = critDamageRule{policy, extraDice}
+	return nil
+}
+
+// Returns the ammount of extra dice a critical DiceRoll of diceAmmount dice rolls.
+func (rule critDamageRule) rolledDice(diceAmmount int) int {
+	rolled := rule.extraDice
+	if rule.policy == CritDoubleDice {
+		rolled += diceAmmount
+	}
+	return rolled
+}
+
+// Returns the ammount of dice a critical DiceRoll of diceAmmount dice adds at their max roll.
+func (rule critDamageRule) maxDice(diceAmmount int) int {
+	if rule.policy == CritMaxPlusRoll {
+		return diceAmmount
+	}
+	return 0
+}
@@ -0,0 +1,65 @@
+package diceroller
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCritDamagePolicies(t *testing.T) {
+	// Mean of "crit 2d6+3" for each policy and extra dice
+	wantedMeans := []struct {
+		policy    CritDamagePolicy
+		extraDice int
+		mean      float64
+	}{
+		{CritDoubleDice, 0, 17},
+		{CritMaxPlusRoll, 0, 22},
+		{CritDoubleTotal, 0, 20},
+		{CritDoubleDice, 1, 20.5},
+		{CritMaxPlusRoll, 2, 29},
+	}
+
+	for i := range wantedMeans {
+		roller := NewPCGRoller(1, 2)
+		if policyErr := roller.SetCritDamagePolicy(wantedMeans[i].policy, wantedMeans[i].extraDice); policyErr != nil {
+			t.Fatalf("Valid critical damage policy returned an error: %s", policyErr.Error())
+		}
+
+		distribution, _ := roller.RollArgsDistribution("crit", "2d6+3")
+		if math.Abs(distribution.Mean()-wantedMeans[i].mean) > 1e-9 {
+			t.Fatalf("Policy %d with %d extra dice mean is %f, expected %f",
+				wantedMeans[i].policy, wantedMeans[i].extraDice, distribution.Mean(), wantedMeans[i].mean)
+		}
+
+		sum := 0
+		for r := 0; r < 10000; r++ {
+			sum += roller.PerformRollArgsAndSum("crit", "2d6+3")
+		}
+		if mean := float64(sum) / 10000; math.Abs(mean-wantedMeans[i].mean) > 0.2 {
+			t.Fatalf("Policy %d with %d extra dice simulated mean is %f, expected %f",
+				wantedMeans[i].policy, wantedMeans[i].extraDice, mean, wantedMeans[i].mean)
+		}
+	}
+}
+
+func TestCritDice(t *testing.T) {
+	roller := NewRoller(maxSource{})
+	roller.SetCritDamagePolicy(CritMaxPlusRoll, 1)
+
+	// Critical hit propagates to the damage DiceRoll
+	results, _ := roller.PerformRollArgs("hit", "1d20", "dmg", "2d8+2")
+	damage := results[1].results[0]
+	if len(damage.dice) != 5 || len(damage.critDice) != 3 || damage.sum != 42 {
+		t.Fatalf("Max plus roll crit with 1 extra die rolled %v, crit dice %v, sum %d", damage.dice, damage.critDice, damage.sum)
+	}
+
+	roller.SetCritDamagePolicy(CritDoubleTotal, 0)
+	results, _ = roller.PerformRollArgs("crit", "2d8+2")
+	if damage := results[0].results[0]; len(damage.critDice) != 0 || damage.sum != 36 {
+		t.Fatalf("Double total crit rolled %v, sum %d", damage.dice, damage.sum)
+	}
+
+	if roller.SetCritDamagePolicy(0, 0) == nil || roller.SetCritDamagePolicy(CritDoubleDice, -1) == nil {
+		t.Fatal("Invalid critical damage policy returned no error")
+	}
+}
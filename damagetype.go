@@ -0,0 +1,126 @@
+package diceroller
+
+import (
+	"fmt"
+	"slices"
+)
+
+// A DamageType tags the damage dealt by DiceRolls, such as fire or slashing.
+type DamageType int
+
+// DamageType values. 0 is untyped damage.
+const (
+	DamageUntyped     DamageType = iota
+	DamageAcid        DamageType = iota
+	DamageBludgeoning DamageType = iota
+	DamageCold        DamageType = iota
+	DamageFire        DamageType = iota
+	DamageForce       DamageType = iota
+	DamageLightning   DamageType = iota
+	DamageNecrotic    DamageType = iota
+	DamagePiercing    DamageType = iota
+	DamagePoison      DamageType = iota
+	DamagePsychic     DamageType = iota
+	DamageRadiant     DamageType = iota
+	DamageSlashing    DamageType = iota
+	DamageThunder     DamageType = iota
+)
+
+// Allowed DamageType strings as RollArg.
+var damageTypeMap = map[string]DamageType{
+	"acid":        DamageAcid,
+	"bludgeoning": DamageBludgeoning,
+	"cold":        DamageCold,
+	"fire":        DamageFire,
+	"force":       DamageForce,
+	"lightning":   DamageLightning,
+	"necrotic":    DamageNecrotic,
+	"piercing":    DamagePiercing,
+	"poison":      DamagePoison,
+	"psychic":     DamagePsychic,
+	"radiant":     DamageRadiant,
+	"slashing":    DamageSlashing,
+	"thunder":     DamageThunder,
+}
+
+// A TargetProfile lists the damage types a target resists, is vulnerable to or is immune to.
+type TargetProfile struct {
+	Resistances     []DamageType // Damage of these types is halved, rounded down
+	Vulnerabilities []DamageType // Damage of these types is doubled
+	Immunities      []DamageType // Damage of these types is ignored
+}
+
+// Checks if the rollArg is a DamageType. Returns the DamageType value if it matches, otherwise untyped.
+func checkForDamageType(rollArg string) DamageType {
+	return damageTypeMap[rollArg]
+}
+
+// Human readable DamageType string.
+func (damageType DamageType) String() string {
+	if damageType == DamageUntyped {
+		return "untyped"
+	}
+	return rollAttributeMapKey(damageTypeMap, damageType)
+}
+
+// Returns the damage sum of each DamageType of rollResults. Formulas take the DamageType of their first DiceRoll.
+func DamageSumsByType(rollResults ...RollResult) map[DamageType]int {
+	sums := make(map[DamageType]int)
+	for e := range rollResults {
+		for i := range rollResults[e].results {
+			result := rollResults[e].results[i]
+			sums[result.diceRoll.damageType()] += result.sum
+		}
+		for f := range rollResults[e].formulaResults {
+			result := rollResults[e].formulaResults[f]
+			sums[result.formula.diceRolls[0].damageType()] += result.sum
+		}
+	}
+	return sums
+}
+
+// Returns the damage taken by the target for each DamageType of rollResults. Following 5e order of operations,
+// each DamageType total is ignored on immunity, else halved on resistance, then doubled on vulnerability.
+func (profile TargetProfile) DamageTakenByType(rollResults ...RollResult) map[DamageType]int {
+	taken := DamageSumsByType(rollResults...)
+	for damageType := range taken {
+		if damageType == DamageUntyped {
+			continue
+		}
+		if slices.Contains(profile.Immunities, damageType) {
+			taken[damageType] = 0
+			continue
+		}
+		if slices.Contains(profile.Resistances, damageType) {
+			taken[damageType] /= 2 // Rounded down, 1 damage resisted is 0
+		}
+		if slices.Contains(profile.Vulnerabilities, damageType) {
+			taken[damageType] *= 2
+		}
+	}
+	return taken
+}
+
+// Returns the total damage taken by the target from rollResults.
+func (profile TargetProfile) DamageTaken(rollResults ...RollResult) (sum int) {
+	for _, taken := range profile.DamageTakenByType(rollResults...) {
+		sum += taken
+	}
+	return
+}
+
+// Tags diceRolls and the DiceRolls of formulas with damageType. Returns an error if there's nothing to tag.
+func tagDamageType(damageType DamageType, diceRolls []DiceRoll, formulas []rollFormula) error {
+	if len(diceRolls) == 0 && len(formulas) == 0 {
+		return fmt.Errorf("damage type %s doesn't follow a dice RollArg", damageType)
+	}
+	for i := range diceRolls {
+		diceRolls[i].rollAttribs.damageType = damageType
+	}
+	for f := range formulas {
+		for i := range formulas[f].diceRolls {
+			formulas[f].diceRolls[i].rollAttribs.damageType = damageType
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package diceroller
+
+import (
+	"testing"
+)
+
+func TestDamageSumsByType(t *testing.T) {
+	// Max source rolls the highest face of every die
+	results, argErrs := NewRoller(maxSource{}).PerformRollArgs("dmg", "2d6", "fire", "1d8", "slashing", "1d4+1", "(1d6+1)*2", "cold")
+	if len(argErrs) > 0 {
+		t.Fatalf("Unexpected damage type errors: %v", argErrs)
+	}
+
+	wantedSums := map[DamageType]int{DamageFire: 12, DamageSlashing: 8, DamageUntyped: 5, DamageCold: 14}
+	sums := DamageSumsByType(results...)
+	for damageType, wanted := range wantedSums {
+		if sums[damageType] != wanted {
+			t.Fatalf("%s damage sum is %d, wanted %d", damageType, sums[damageType], wanted)
+		}
+	}
+	if total := RollResultsSum(results...); total != 39 {
+		t.Fatalf("Typed damage total is %d, wanted 39", total)
+	}
+}
+
+func TestTargetProfile(t *testing.T) {
+	results, _ := NewRoller(maxSource{}).PerformRollArgs("1d7", "fire", "1d8", "slashing", "1d4+1", "1d6", "cold")
+
+	profiles := []struct {
+		profile TargetProfile
+		taken   int
+	}{
+		{TargetProfile{}, 26},
+		{TargetProfile{Resistances: []DamageType{DamageFire}}, 3 + 8 + 5 + 6},
+		{TargetProfile{Vulnerabilities: []DamageType{DamageSlashing}}, 7 + 16 + 5 + 6},
+		{TargetProfile{[]DamageType{DamageFire}, []DamageType{DamageFire}, []DamageType{DamageCold}}, 6 + 8 + 5},
+	}
+	for i := range profiles {
+		if taken := profiles[i].profile.DamageTaken(results...); taken != profiles[i].taken {
+			t.Fatalf("Target profile %v takes %d damage, wanted %d", profiles[i].profile, taken, profiles[i].taken)
+		}
+	}
+
+	// Resisted damage rounds down to 0, unlike the half rollAttribute
+	results, _ = NewRoller(minSource{}).PerformRollArgs("1d6", "fire")
+	resistant := TargetProfile{[]DamageType{DamageFire}, []DamageType{DamageFire}, nil}
+	if taken := resistant.DamageTaken(results...); taken != 0 {
+		t.Fatalf("Resistant target takes %d damage from 1 fire damage, wanted 0", taken)
+	}
+}
+
+func TestInvalidDamageType(t *testing.T) {
+	if _, argErrs := PerformRollArgs("fire", "1d6"); len(argErrs) == 0 {
+		t.Fatal("Damage type without DiceRoll returned no error")
+	}
+	if _, argErrs := PerformRollArgs("1d6", "fire", "cold"); len(argErrs) == 0 {
+		t.Fatal("Second damage type for the same DiceRoll returned no error")
+	}
+}
@@ -4,66 +4,100 @@ package diceroller
 
 import (
 	"math"
-	"math/rand"
-	"slices"
 )
 
 // Straightforward rolling using RollArgs. Returns the sum, invalid RollArgs are worth 0.
 func PerformRollArgsAndSum(rollArgs ...string) int {
-	rollExprs, _ := parseRollArgs(rollArgs...)
-	return performRollingExpressionsAndSum(rollExprs...)
+	return defaultRoller.PerformRollArgsAndSum(rollArgs...)
 }
 
-// Performs an array of RollArgs. Returns a rollResult array for valid RollArgs and an error array for invalid ones.
-func PerformRollArgs(rollArgs ...string) ([]rollResult, []error) {
-	rollExprs, argErrs := parseRollArgs(rollArgs...)
-	results, diceErrs := performRollingExpressions(rollExprs...)
-	return results, append(argErrs, diceErrs...)
+// Performs an array of RollArgs. Returns a RollResult array for valid RollArgs and an error array for invalid ones.
+func PerformRollArgs(rollArgs ...string) ([]RollResult, []error) {
+	return defaultRoller.PerformRollArgs(rollArgs...)
 }
 
 // Performs an array of DiceRoll. Returns the sum, invalid DiceRolls are worth 0.
 func PerformDiceRollsAndSum(diceRolls ...DiceRoll) int {
-	results, _ := PerformDiceRolls(diceRolls...)
-	return RollResultsSum(results...)
+	return defaultRoller.PerformDiceRollsAndSum(diceRolls...)
 }
 
-// Performs an array of DiceRoll. Returns a rollResult array for valid DiceRolls and an error array for invalid ones.
-func PerformDiceRolls(diceRolls ...DiceRoll) (results []rollResult, diceErrs []error) {
-	return performRollingExpressions(*newRollingExpression(diceRolls...))
+// Performs an array of DiceRoll. Returns a RollResult array for valid DiceRolls and an error array for invalid ones.
+func PerformDiceRolls(diceRolls ...DiceRoll) (results []RollResult, diceErrs []error) {
+	return defaultRoller.PerformDiceRolls(diceRolls...)
 }
 
-// Performs a rolling expression. Returns the sum, invalid DiceRolls are worth 0.
-func performRollingExpressionsAndSum(rollExprs ...rollingExpression) int {
-	results, _ := performRollingExpressions(rollExprs...)
-	return RollResultsSum(results...)
+// Performs a rolling expression with the default Roller. Returns a RollResult array for valid DiceRolls and an error array for invalid ones.
+func performRollingExpressions(rollExprs ...rollingExpression) (results []RollResult, diceErrs []error) {
+	return defaultRoller.performRollingExpressions(rollExprs...)
 }
 
-// Performs a rolling expression. Returns a rollResult array for valid DiceRolls and an error array for invalid ones.
-func performRollingExpressions(rollExprs ...rollingExpression) (results []rollResult, diceErrs []error) {
-	wasCritHit := false
+// Validates and performs diceRoll with the default Roller. Returns a DiceRollResult if valid, an error if invalid.
+func validateAndperformRoll(diceRoll DiceRoll) (*DiceRollResult, error) {
+	return defaultRoller.validateAndperformRoll(diceRoll)
+}
+
+// Performs a rolling expression. Returns a RollResult array for valid DiceRolls and an error array for invalid ones.
+// A critical hit scored by an attack expression applies the crit rollAttribute to the damage expressions it owns.
+func (roller *Roller) performRollingExpressions(rollExprs ...rollingExpression) (results []RollResult, diceErrs []error) {
+	attackCritHit := false
 	for e := range rollExprs {
-		rollExprResult := newRollResult()
-		for i := range rollExprs[e].diceRolls {
-			diceRoll := rollExprs[e].diceRolls[i]
-			if wasCritHit {
-				diceRoll.rollAttribs.setRollAttrib(critAttrib)
-			}
-			if result, diceErr := validateAndperformRoll(diceRoll); diceErr == nil {
-				rollExprResult.results = append(rollExprResult.results, *result)
-			} else {
-				diceErrs = append(diceErrs, diceErr)
-			}
+		rollExprResult, exprErrs := roller.performRollingExpression(rollExprs[e], attackCritHit && rollExprs[e].kind == damageExpression)
+		diceErrs = append(diceErrs, exprErrs...)
+
+		// Attacks own the following damage expressions, until another attack or plain expression
+		switch rollExprs[e].kind {
+		case attackExpression:
+			attackCritHit = rollExprResult.CritHit()
+		case plainExpression:
+			attackCritHit = false
 		}
 
-		wasCritHit = rollExprResult.detectScoredCritHit()
-
 		results = append(results, *rollExprResult)
 	}
 	return results, diceErrs
 }
 
+// Performs a single rolling expression, applying the crit rollAttribute if critHit. Returns a RollResult
+// for valid DiceRolls and an error array for invalid ones.
+func (roller *Roller) performRollingExpression(rollExpr rollingExpression, critHit bool) (*RollResult, []error) {
+	var diceErrs []error
+	rollExprResult := newRollResult()
+	rollExprResult.kind = rollExpr.kind
+	if rollSeed, seeded := roller.rollSeed(); seeded {
+		rollExprResult.seed = &rollSeed
+	}
+
+	for i := range rollExpr.diceRolls {
+		diceRoll := rollExpr.diceRolls[i]
+		if critHit {
+			diceRoll = diceRoll.withAttrib(CritAttrib)
+		}
+		if result, diceErr := roller.validateAndperformRoll(diceRoll); diceErr == nil {
+			rollExprResult.results = append(rollExprResult.results, *result)
+		} else {
+			diceErrs = append(diceErrs, diceErr)
+		}
+	}
+	for f := range rollExpr.formulas {
+		formula := rollExpr.formulas[f]
+		if critHit {
+			formula.diceRolls = append([]DiceRoll{}, formula.diceRolls...)
+			for i := range formula.diceRolls {
+				formula.diceRolls[i] = formula.diceRolls[i].withAttrib(CritAttrib)
+			}
+		}
+		if result, diceErr := roller.validateAndPerformFormula(formula); diceErr == nil {
+			rollExprResult.formulaResults = append(rollExprResult.formulaResults, *result)
+		} else {
+			diceErrs = append(diceErrs, diceErr)
+		}
+	}
+
+	return rollExprResult, diceErrs
+}
+
 // Validates and performs diceRoll. Returns a DiceRollResult if valid, an error if invalid.
-func validateAndperformRoll(diceRoll DiceRoll) (*diceRollResult, error) {
+func (roller *Roller) validateAndperformRoll(diceRoll DiceRoll) (*DiceRollResult, error) {
 	// Validate DiceRoll
 	if diceErr := validateDiceRoll(diceRoll); diceErr != nil {
 		// Invalid DiceRoll, return error
@@ -71,81 +105,189 @@ func validateAndperformRoll(diceRoll DiceRoll) (*diceRollResult, error) {
 	}
 
 	// Valid DiceRoll. Generate and return DiceRollResult
-	return performRoll(diceRoll), nil
+	return roller.performRoll(diceRoll), nil
 }
 
-// Generates DiceRollResult and applies attribs.
-func performRoll(diceRoll DiceRoll) *diceRollResult {
+// Generates DiceRollResult through the Roller RollPipeline, then detects critical hits and fails.
+func (roller *Roller) performRoll(diceRoll DiceRoll) *DiceRollResult {
 	diceRollResult := newDiceRollResult(diceRoll)
+	state := &RollState{roller, diceRollResult, diceRoll.attributeHooks(), []rolledDie{}}
 
-	// Generate rolls
-	generateRolls(diceRoll, diceRollResult)
+	roller.rollPipeline().apply(state)
 
-	// Drop High attrib
-	if diceRoll.hasAttrib(dropHighAttrib) && len(diceRollResult.dice) > 1 {
-		dropHigh(diceRollResult)
-	}
+	// Critical hits and fails
+	roller.detectCrits(state)
 
-	// Drop Low attrib
-	if diceRoll.hasAttrib(dropLowAttrib) && len(diceRollResult.dice) > 1 {
-		dropLow(diceRollResult)
-	}
+	return diceRollResult
+}
+
+// Applies the modifier, critical total, half, minimum and minus rules of diceRoll to the sum of its dice,
+// as the default RollPipeline does. Returns the DiceRoll result.
+func (roller *Roller) applySumRules(diceRoll DiceRoll, sum int) int {
+	sum = applyModifier(diceRoll, sum)
+	sum = roller.applyCritTotal(diceRoll, sum)
+	sum = applyHalf(diceRoll, sum)
+	sum = applyMinimum(diceRoll, sum)
+	return applyMinus(diceRoll, sum)
+}
 
-	// Apply modifier
-	diceRollResult.sum += diceRoll.modifier
+// Applies the modifier, it adds successes to dice pools. Returns the new sum.
+func applyModifier(diceRoll DiceRoll, sum int) int {
+	return sum + diceRoll.modifier
+}
 
-	// Half attrib
-	if diceRoll.hasAttrib(halfAttrib) {
-		diceRollResult.sum = halve(diceRollResult.sum)
+// Doubles the sum of critical DiceRolls with the CritDoubleTotal policy, modifier included. Returns the new sum.
+func (roller *Roller) applyCritTotal(diceRoll DiceRoll, sum int) int {
+	if diceRoll.hasAttrib(CritAttrib) && roller.critDamage.policy == CritDoubleTotal {
+		sum *= 2
 	}
+	return sum
+}
 
-	// Minimum roll result is always 1, even after applying negative modifiers and half
-	if diceRollResult.sum <= 0 {
-		diceRollResult.sum = 1
+// Halves the sum of half DiceRolls, except dice pools. Returns the new sum.
+func applyHalf(diceRoll DiceRoll, sum int) int {
+	if diceRoll.hasAttrib(HalfAttrib) && diceRoll.successRule() == nil {
+		sum = halve(sum)
 	}
+	return sum
+}
 
-	// Negative Sum if minus DiceRoll
-	if diceRoll.hasAttrib(minusAttrib) {
-		diceRollResult.sum = -diceRollResult.sum
+// Minimum roll result is always 1, even after applying negative modifiers and half, except dice pools. Returns the new sum.
+func applyMinimum(diceRoll DiceRoll, sum int) int {
+	if sum <= 0 && diceRoll.successRule() == nil {
+		sum = 1
 	}
+	return sum
+}
 
-	return diceRollResult
+// Negative sum if minus DiceRoll. Returns the new sum.
+func applyMinus(diceRoll DiceRoll, sum int) int {
+	if diceRoll.hasAttrib(MinusAttrib) {
+		sum = -sum
+	}
+	return sum
 }
 
-func generateRolls(diceRoll DiceRoll, diceRollResult *diceRollResult) {
+// Generates the dice of diceRoll into state, applying PerDie hooks to each of them.
+func (roller *Roller) generateRolls(diceRoll DiceRoll, state *RollState) {
+	diceRollResult, hooks := state.result, state.hooks
+
 	// Determine actual dice ammount to roll
-	actualDiceAmmount := diceRoll.diceAmmount
+	actualDiceAmmount, maxDiceAmmount := diceRoll.diceAmmount, 0
 
 	// Crit attrib
-	if diceRoll.hasAttrib(critAttrib) {
-		actualDiceAmmount = actualDiceAmmount * 2
+	if diceRoll.hasAttrib(CritAttrib) {
+		actualDiceAmmount += roller.critDamage.rolledDice(diceRoll.diceAmmount)
+		maxDiceAmmount = roller.critDamage.maxDice(diceRoll.diceAmmount)
 	}
 
 	// Generate rolls
 	for i := 0; i < actualDiceAmmount; i++ {
-		roll := rollDice(diceRoll.diceSize)
+		roll := roller.rollAndReroll(diceRoll, diceRollResult)
 
 		// Advantage attrib
-		if diceRoll.hasAttrib(advantageAttrib) {
-			roll = advantage(roll, rollDice(diceRoll.diceSize), diceRollResult)
+		if diceRoll.hasAttrib(AdvantageAttrib) {
+			roll = advantage(roll, roller.rollAndReroll(diceRoll, diceRollResult), diceRollResult)
 		}
 		// Disadvantage attrib
-		if diceRoll.hasAttrib(disadvantageAttrib) {
-			roll = disadvantage(roll, rollDice(diceRoll.diceSize), diceRollResult)
+		if diceRoll.hasAttrib(DisadvantageAttrib) {
+			roll = disadvantage(roll, roller.rollAndReroll(diceRoll, diceRollResult), diceRollResult)
+		}
+		rolled := rolledDie{roll, 0, -1}
+
+		// Exploding dice
+		if rule := diceRoll.explodeRule(); rule != nil {
+			chains := len(diceRollResult.explosions)
+			roll, rolled.extraSum = roller.explode(roll, diceRoll.diceSize, *rule, diceRollResult)
+			if len(diceRollResult.explosions) > chains {
+				rolled.chain = chains
+			}
 		}
+		// Custom rollAttributes
+		roll = applyPerDieHooks(hooks, roll, diceRoll.diceSize)
 
 		diceRollResult.dice = append(diceRollResult.dice, roll)
-		diceRollResult.sum += roll
+		diceRollResult.sum += roll + rolled.extraSum
+		state.rolled = append(state.rolled, rolled)
+
+		// Dice rolled because of a crit
+		if i >= diceRoll.diceAmmount {
+			diceRollResult.critDice = append(diceRollResult.critDice, roll)
+		}
+	}
+
+	// Crit dice added at their max roll
+	for i := 0; i < maxDiceAmmount; i++ {
+		diceRollResult.dice = append(diceRollResult.dice, diceRoll.diceSize)
+		diceRollResult.sum += diceRoll.diceSize
+		state.rolled = append(state.rolled, rolledDie{diceRoll.diceSize, 0, -1})
+		diceRollResult.critDice = append(diceRollResult.critDice, diceRoll.diceSize)
 	}
 }
 
-// Generates a single die roll.
-func rollDice(diceSize int) int {
-	return rand.Intn(diceSize) + 1
+// Generates a single die roll from the Roller random source.
+func (roller *Roller) rollDice(diceSize int) int {
+	return roller.rand.IntN(diceSize) + 1
+}
+
+// Generates a single die roll of diceRoll and applies its rerollRule. Returns the roll to keep.
+func (roller *Roller) rollAndReroll(diceRoll DiceRoll, diceRollResult *DiceRollResult) int {
+	roll := roller.rollDice(diceRoll.diceSize)
+
+	rule := diceRoll.rerollRule()
+	if rule == nil {
+		return roll
+	}
+
+	for rerolls := 0; rule.threshold.matches(roll) && rerolls < maxRerollChainLength; rerolls++ {
+		replacement := roller.rollDice(diceRoll.diceSize)
+		diceRollResult.rerolled = append(diceRollResult.rerolled, RerolledDie{roll, replacement})
+		roll = replacement
+
+		// Reroll once keeps the new roll whatever it is
+		if rule.once {
+			break
+		}
+	}
+
+	return roll
+}
+
+// Applies exploding logic, rolling extra dice while they explode. Extra dice are added to the
+// returned roll when compounding, else to the returned extra sum. Returns the roll to keep and the extra sum.
+func (roller *Roller) explode(roll int, diceSize int, rule explodeRule, diceRollResult *DiceRollResult) (toKeep int, extraSum int) {
+	toKeep = roll
+	if !rule.explodes(roll, diceSize) {
+		return toKeep, extraSum
+	}
+
+	chain := ExplosionChain{roll, []int{}}
+	for extraRoll := roll; rule.explodes(extraRoll, diceSize) && len(chain.extra) < maxExplodeChainLength; {
+		extraRoll = roller.rollDice(diceSize)
+		extra := extraRoll
+
+		// Penetrating dice are worth one less
+		if rule.mode == explodePenetrate {
+			extra--
+		}
+
+		chain.extra = append(chain.extra, extra)
+
+		// Compounding dice add up into the exploding die, others add up apart from it
+		if rule.mode == explodeCompound {
+			toKeep += extra
+		} else {
+			extraSum += extra
+		}
+	}
+
+	diceRollResult.explosions = append(diceRollResult.explosions, chain)
+
+	return toKeep, extraSum
 }
 
 // Applies advantage logic. Returns the roll to keep and the roll to drop.
-func advantage(roll int, roll2 int, diceRollResult *diceRollResult) (toKeep int) {
+func advantage(roll int, roll2 int, diceRollResult *DiceRollResult) (toKeep int) {
 	toKeep, toDrop := roll, roll2 // Default return order, change if needed
 
 	if roll != max(roll, roll2) {
@@ -158,7 +300,7 @@ func advantage(roll int, roll2 int, diceRollResult *diceRollResult) (toKeep int)
 }
 
 // Applies disavantage logic. Returns the roll to keep and the roll to drop.
-func disadvantage(roll int, roll2 int, diceRollResult *diceRollResult) (toKeep int) {
+func disadvantage(roll int, roll2 int, diceRollResult *DiceRollResult) (toKeep int) {
 	toKeep, toDrop := roll, roll2 // Default return order, change if needed
 
 	if roll != min(roll, roll2) {
@@ -170,30 +312,6 @@ func disadvantage(roll int, roll2 int, diceRollResult *diceRollResult) (toKeep i
 	return toKeep
 }
 
-// Applies drop high logic. Returns the index of the roll to drop.
-func dropHigh(diceRollResult *diceRollResult) {
-	drop := 0
-
-	drop = slices.Max(diceRollResult.dice)
-	dropIndex := slices.Index(diceRollResult.dice, drop)
-	diceRollResult.highDropped = append(diceRollResult.highDropped, diceRollResult.dice[dropIndex])
-
-	diceRollResult.sum -= diceRollResult.dice[dropIndex]
-	diceRollResult.dice = slices.Delete(diceRollResult.dice, dropIndex, dropIndex+1)
-}
-
-// Applies drop low logic. Returns the index of the roll to drop.
-func dropLow(diceRollResult *diceRollResult) {
-	drop := 0
-
-	drop = slices.Min(diceRollResult.dice)
-	dropIndex := slices.Index(diceRollResult.dice, drop)
-	diceRollResult.lowDropped = append(diceRollResult.lowDropped, diceRollResult.dice[dropIndex])
-
-	diceRollResult.sum -= diceRollResult.dice[dropIndex]
-	diceRollResult.dice = slices.Delete(diceRollResult.dice, dropIndex, dropIndex+1)
-}
-
 // Applies half logic. Rounds down.
 func halve(sum int) int {
 	halved := 0
@@ -3,6 +3,8 @@ package diceroller
 import (
 	"fmt"
 	"math"
+	"slices"
+	"strings"
 )
 
 // A DiceRoll represents a dice rolling expression, such as 1d6 or 2d8+1.
@@ -31,25 +33,37 @@ func NewDiceRollWithAttribs(diceAmmount int, diceSize int, modifier int, attribs
 	}
 	diceRoll := DiceRoll{diceAmmount, diceSize, modifier, attribs}
 	if diceErr := validateDiceRoll(diceRoll); diceErr != nil {
-		return nil, fmt.Errorf("invalid DiceRoll %s", diceErr.Error())
+		return nil, diceErr
 	}
 	return &diceRoll, nil
 }
 
+// Parses a DiceRoll string of space separated RollArgs, such as "adv 1d20+5". Parsing the String of a DiceRoll
+// returns the same DiceRoll. Returns an error if invalid or if diceRollStr isn't a single DiceRoll.
+func ParseDiceRoll(diceRollStr string) (*DiceRoll, error) {
+	rollExprs, argErrs := parseRollArgs(strings.Fields(diceRollStr)...)
+	if len(argErrs) > 0 {
+		return nil, argErrs[0]
+	}
+	if len(rollExprs) != 1 || len(rollExprs[0].diceRolls) != 1 || len(rollExprs[0].formulas) > 0 {
+		return nil, fmt.Errorf("invalid DiceRoll: %s is not a single DiceRoll", diceRollStr)
+	}
+	return &rollExprs[0].diceRolls[0], nil
+}
+
 // DiceRoll constructor, validates values but doesn't return errors. Can be useful for testing.
 func newDiceRoll(diceAmmount int, diceSize int, modifier int) *DiceRoll {
 	diceRoll, _ := NewDiceRoll(diceAmmount, diceSize, modifier)
 	return diceRoll
 }
 
-// Performs the DiceRoll. Returns the sum if valid, zero if invalid.
+// Performs the DiceRoll with the default Roller. Returns the sum if valid, zero if invalid.
 func (diceRoll DiceRoll) Roll() int {
-	result, _ := validateAndperformRoll(diceRoll)
-	return result.sum
+	return defaultRoller.Roll(diceRoll)
 }
 
 // Returns true if wanted is set. Provides nil protection that rollAttribute can't provide itself.
-func (diceRoll DiceRoll) hasAttrib(wanted rollAttribute) bool {
+func (diceRoll DiceRoll) hasAttrib(wanted RollAttribute) bool {
 	found := false
 	if diceRoll.rollAttribs != nil {
 		found = diceRoll.rollAttribs.hasAttrib(wanted)
@@ -57,18 +71,179 @@ func (diceRoll DiceRoll) hasAttrib(wanted rollAttribute) bool {
 	return found
 }
 
-// Human readable DiceRoll string, such as "2d8+1".
+// Returns a copy of the DiceRoll with attrib set, leaving the shared rollAttributes untouched.
+func (diceRoll DiceRoll) withAttrib(attrib RollAttribute) DiceRoll {
+	attribs := newRollAttributes()
+	if diceRoll.rollAttribs != nil {
+		attribs = diceRoll.rollAttribs.clone()
+	}
+	attribs.setRollAttrib(attrib)
+	diceRoll.rollAttribs = attribs
+	return diceRoll
+}
+
+// Returns the ammount of dice to roll.
+func (diceRoll DiceRoll) DiceAmmount() int {
+	return diceRoll.diceAmmount
+}
+
+// Returns the ammount of faces of the dice.
+func (diceRoll DiceRoll) DiceSize() int {
+	return diceRoll.diceSize
+}
+
+// Returns the modifier added to the dice sum.
+func (diceRoll DiceRoll) Modifier() int {
+	return diceRoll.modifier
+}
+
+// Returns the rollAttribute strings of the DiceRoll, sorted.
+func (diceRoll DiceRoll) attributeStrs() []string {
+	attribStrs := make([]string, 0)
+	if diceRoll.rollAttribs != nil {
+		for rollAttrib := range diceRoll.rollAttribs.attribs {
+			attribStrs = append(attribStrs, rollAttributeStr(rollAttrib))
+		}
+	}
+	slices.Sort(attribStrs)
+	return attribStrs
+}
+
+// Returns the explodeRule, nil if dice don't explode. Provides nil protection for rollAttributes.
+func (diceRoll DiceRoll) explodeRule() *explodeRule {
+	var rule *explodeRule
+	if diceRoll.rollAttribs != nil {
+		rule = diceRoll.rollAttribs.explode
+	}
+	return rule
+}
+
+// Returns the rerollRule, nil if dice aren't rerolled. Provides nil protection for rollAttributes.
+func (diceRoll DiceRoll) rerollRule() *rerollRule {
+	var rule *rerollRule
+	if diceRoll.rollAttribs != nil {
+		rule = diceRoll.rollAttribs.reroll
+	}
+	return rule
+}
+
+// Returns the keepDropRules. Provides nil protection for rollAttributes.
+func (diceRoll DiceRoll) keepDropRules() []keepDropRule {
+	var rules []keepDropRule
+	if diceRoll.rollAttribs != nil {
+		rules = diceRoll.rollAttribs.keepDrop
+	}
+	return rules
+}
+
+// Returns the successRule, nil if dice are summed. Provides nil protection for rollAttributes.
+func (diceRoll DiceRoll) successRule() *successRule {
+	var rule *successRule
+	if diceRoll.rollAttribs != nil {
+		rule = diceRoll.rollAttribs.success
+	}
+	return rule
+}
+
+// Returns the critRange, nil to use the Roller range. Provides nil protection for rollAttributes.
+func (diceRoll DiceRoll) critRange() *critRange {
+	var crits *critRange
+	if diceRoll.rollAttribs != nil {
+		crits = diceRoll.rollAttribs.crits
+	}
+	return crits
+}
+
+// Returns the DamageType, untyped by default. Provides nil protection for rollAttributes.
+func (diceRoll DiceRoll) damageType() DamageType {
+	damageType := DamageUntyped
+	if diceRoll.rollAttribs != nil {
+		damageType = diceRoll.rollAttribs.damageType
+	}
+	return damageType
+}
+
+// Canonical DiceRoll string, the RollArgs parsing back to the same DiceRoll, such as "adv 1d20cs>=19+5" or
+// "droplow 4d6kh3 fire". Flag rollAttributes and keep or drop aliases come first, the damage type last.
 func (diceRoll DiceRoll) String() string {
+	rollArgs := make([]string, 0)
+
+	// Add flag rollAttributes, minus is the leading minus symbol of the dice
+	for _, attribStr := range diceRoll.attributeStrs() {
+		if attribStr != minusAttribStr {
+			rollArgs = append(rollArgs, attribStr)
+		}
+	}
+
+	// Add keep and drop rules following the first one, applied from aliases
+	if rules := diceRoll.keepDropRules(); len(rules) > 1 {
+		for _, rule := range rules[1:] {
+			rollArgs = append(rollArgs, rule.aliasString())
+		}
+	}
+
+	rollArgs = append(rollArgs, diceRoll.diceString())
+
+	// Add damage type
+	if damageType := diceRoll.damageType(); damageType != DamageUntyped {
+		rollArgs = append(rollArgs, damageType.String())
+	}
+
+	return strings.Join(rollArgs, " ")
+}
+
+// Encodes the DiceRoll as its String.
+func (diceRoll DiceRoll) MarshalText() ([]byte, error) {
+	return []byte(diceRoll.String()), nil
+}
+
+// Decodes a DiceRoll String. Returns an error if invalid.
+func (diceRoll *DiceRoll) UnmarshalText(text []byte) error {
+	decoded, argErr := ParseDiceRoll(string(text))
+	if argErr != nil {
+		return argErr
+	}
+	*diceRoll = *decoded
+	return nil
+}
+
+// Dice RollArg string, such as "-4d6kh3+2", without flag rollAttributes nor damage type.
+func (diceRoll DiceRoll) diceString() string {
 	strDiceRoll := ""
 
 	// Add minus symbol if needed
-	if diceRoll.hasAttrib(minusAttrib) {
+	if diceRoll.hasAttrib(MinusAttrib) {
 		strDiceRoll += "-"
 	}
 
 	// XdY format
 	strDiceRoll += fmt.Sprintf("%dd%d", diceRoll.diceAmmount, diceRoll.diceSize)
 
+	// Add rerolls
+	if reroll := diceRoll.rerollRule(); reroll != nil {
+		strDiceRoll += reroll.String()
+	}
+
+	// Add exploding dice
+	if explode := diceRoll.explodeRule(); explode != nil {
+		strDiceRoll += explode.String()
+	}
+
+	// Add the first keep or drop rule, following ones are aliases
+	if rules := diceRoll.keepDropRules(); len(rules) > 0 {
+		strDiceRoll += rules[0].String()
+	}
+
+	// Add success counting
+	if success := diceRoll.successRule(); success != nil {
+		strDiceRoll += success.String()
+	}
+
+	// Add critical range
+	if crits := diceRoll.critRange(); crits != nil {
+		strDiceRoll += crits.String()
+	}
+
 	// Add modifier when necessary
 	if diceRoll.modifier != 0 {
 		if diceRoll.modifier > 0 {
@@ -80,16 +255,32 @@ func (diceRoll DiceRoll) String() string {
 	return strDiceRoll
 }
 
-// Validates diceRoll values. Returns nil if valid, error if invalid.
+// Validates diceRoll values. Returns nil if valid, a ValidationError if invalid.
 func validateDiceRoll(diceRoll DiceRoll) error {
-	if diceErr := validateDiceAmmout(diceRoll.diceAmmount); diceErr != nil {
-		return fmt.Errorf("%s: %s", diceRoll.String(), diceErr.Error())
+	ammountKind := NoDice
+	if diceRoll.diceAmmount > maxDiceRollValue {
+		ammountKind = TooManyDice
 	}
-	if diceErr := validateDiceSize(diceRoll.diceSize); diceErr != nil {
-		return fmt.Errorf("%s: %s", diceRoll.String(), diceErr.Error())
+
+	// Validated in order, rules only once dice values are valid
+	validations := []struct {
+		kind     ValidationErrorKind
+		validate func() error
+	}{
+		{ammountKind, func() error { return validateDiceAmmout(diceRoll.diceAmmount) }},
+		{BadSize, func() error { return validateDiceSize(diceRoll.diceSize) }},
+		{BadModifier, func() error { return validateDiceModifier(diceRoll.modifier) }},
+		{BadKeepDrop, func() error { return validateKeepDropRules(diceRoll.keepDropRules(), diceRoll.diceAmmount) }},
+		{BadReroll, func() error { return validateRerollRule(diceRoll.rerollRule(), diceRoll.diceSize) }},
+		{BadExplode, func() error { return validateExplodeRule(diceRoll.explodeRule(), diceRoll.diceSize) }},
+		{BadSuccess, func() error { return validateSuccessRule(diceRoll.successRule(), diceRoll.explodeRule()) }},
+		{BadCritRange, func() error { return validateCritRange(diceRoll.critRange(), diceRoll.diceSize) }},
+		{BadAttribute, func() error { return validateAttributeHooks(diceRoll) }},
 	}
-	if diceErr := validateDiceModifier(diceRoll.modifier); diceErr != nil {
-		return fmt.Errorf("%s: %s", diceRoll.String(), diceErr.Error())
+	for i := range validations {
+		if diceErr := validations[i].validate(); diceErr != nil {
+			return &ValidationError{validations[i].kind, diceRoll.String(), diceErr}
+		}
 	}
 	return nil
 }
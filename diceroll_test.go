@@ -1,6 +1,7 @@
 package diceroller
 
 import (
+	"reflect"
 	"regexp"
 	"strings"
 	"testing"
@@ -37,7 +38,7 @@ var validDiceRollsValues = []diceRollTestValues{
 	{
 		`-5d6-1`,
 		regexp.MustCompile(`\[[1-6] [1-6] [1-6] [1-6] [1-6]\]`),
-		DiceRoll{5, 6, -1, newRollAttributes(minusAttrib)},
+		DiceRoll{5, 6, -1, newRollAttributes(MinusAttrib)},
 	},
 	{
 		`1d2-4`,
@@ -71,7 +72,7 @@ var invalidDiceRollsValues = []diceRollTestValues{
 	{
 		`-1d0`,
 		nil,
-		DiceRoll{1, 0, 0, newRollAttributes(minusAttrib)},
+		DiceRoll{1, 0, 0, newRollAttributes(MinusAttrib)},
 	},
 }
 
@@ -110,6 +111,46 @@ func validateDiceRollString(diceValues diceRollTestValues, t *testing.T) {
 func FuzzNewDiceRoll(f *testing.F) {
 	f.Add(2, 8, 1)
 	f.Fuzz(func(t *testing.T, diceAmmount int, diceSize int, modifier int) {
-		NewDiceRollWithAttribs(diceAmmount, diceSize, modifier, nil)
+		diceRoll, diceErr := NewDiceRollWithAttribs(diceAmmount, diceSize, modifier, nil)
+		if diceErr != nil {
+			return
+		}
+		if reparsed, argErr := ParseDiceRoll(diceRoll.String()); argErr != nil || !reflect.DeepEqual(reparsed, diceRoll) {
+			t.Fatalf("DiceRoll %s parsed back to %v, %v", diceRoll, reparsed, argErr)
+		}
 	})
 }
+
+func TestParseDiceRoll(t *testing.T) {
+	diceRollStrs := []string{
+		"4d6", "adv 1d20+5", "-2d8-1", "drophigh droplow 4d6", "crit dmg hit 2d6r1!!>5kh1+3 fire",
+		"dis spell 1d20cs>=19cf<=2", "8d10>=7f1", "half 1d6 cold", "adv advantage 1d20"}
+
+	for i := range diceRollStrs {
+		diceRoll, argErr := ParseDiceRoll(diceRollStrs[i])
+		if argErr != nil {
+			t.Fatalf("DiceRoll %s returned error: %s", diceRollStrs[i], argErr)
+		}
+		reparsed, argErr := ParseDiceRoll(diceRoll.String())
+		if argErr != nil || !reflect.DeepEqual(reparsed, diceRoll) {
+			t.Fatalf("DiceRoll %s parsed back from %s to %v, %v", diceRollStrs[i], diceRoll, reparsed, argErr)
+		}
+
+		// TextMarshaler round trip
+		text, _ := diceRoll.MarshalText()
+		var decoded DiceRoll
+		if textErr := decoded.UnmarshalText(text); textErr != nil || !reflect.DeepEqual(&decoded, diceRoll) {
+			t.Fatalf("DiceRoll %s decoded from text to %v, %v", diceRoll, decoded, textErr)
+		}
+	}
+
+	if diceRoll, _ := ParseDiceRoll("drophigh adv 4d6 fire"); diceRoll.String() != "adv 4d6dh1 fire" {
+		t.Fatalf("Canonical DiceRoll string is %s, wanted adv 4d6dh1 fire", diceRoll)
+	}
+
+	for _, invalid := range []string{"", "adv", "1d6 1d8", "(1d8+2)*2", "1d6 hit 1d20", "fire 1d6"} {
+		if _, argErr := ParseDiceRoll(invalid); argErr == nil {
+			t.Fatalf("Invalid DiceRoll %q returned no error", invalid)
+		}
+	}
+}
@@ -41,7 +41,7 @@ func TestPerformRollsWithValidDiceRolls(t *testing.T) {
 
 	}
 
-	// One rollResult with one diceRollResult per valid DiceRoll should be received
+	// One RollResult with one DiceRollResult per valid DiceRoll should be received
 	if lenResults, lenValues := len(results[0].results), len(validDiceRollsValues); lenResults != lenValues {
 		// Missing results, fail the test
 		t.Fatalf("Result list length = %d, wanted %d", lenResults, lenValues)
@@ -108,7 +108,7 @@ func TestTrickyRolls(t *testing.T) {
 }
 
 // Validates roll result matches expected format
-func validateDiceRollResult(result diceRollResult, diceValues diceRollTestValues, t *testing.T) {
+func validateDiceRollResult(result DiceRollResult, diceValues diceRollTestValues, t *testing.T) {
 	// validate result format
 	if resultStr := result.String(); !diceValues.resultFormat.MatchString(result.String()) {
 		t.Fatalf("Roll result = %s, wanted %#q", resultStr, diceValues.resultFormat)
@@ -123,7 +123,7 @@ func validateDiceRollResult(result diceRollResult, diceValues diceRollTestValues
 	if sum <= 0 {
 		sum = 1
 	}
-	if diceValues.diceRoll.hasAttrib(minusAttrib) {
+	if diceValues.diceRoll.hasAttrib(MinusAttrib) {
 		sum = -sum
 	}
 
@@ -147,7 +147,7 @@ func TestDnDHitRollUntilCrit(t *testing.T) {
 	for {
 		results, _ := PerformRollArgs("hit", "1d20", "dmg", "2d6+3")
 
-		if results[0].detectScoredCritHit() {
+		if results[0].CritHit() {
 			break
 		}
 	}
@@ -2,28 +2,34 @@ package diceroller
 
 import (
 	"fmt"
-	"sort"
-
-	"golang.org/x/exp/maps"
+	"slices"
 )
 
-// A diceRollResult contains the results of performing a DiceRoll
-type diceRollResult struct {
-	diceRoll      DiceRoll // Performed DiceRoll
-	dice          []int    // Individual dice roll result
-	sum           int      // Sum of Dice
-	advDisDropped []int    // Dropped advantage/disadvantage dice
-	highDropped   []int    // Dropped high dice
-	lowDropped    []int    // Dropped low dice
+// A DiceRollResult contains the results of performing a DiceRoll
+type DiceRollResult struct {
+	diceRoll      DiceRoll         // Performed DiceRoll
+	dice          []int            // Individual dice roll result
+	sum           int              // Sum of Dice
+	advDisDropped []int            // Dropped advantage/disadvantage dice
+	highDropped   []int            // Dropped high dice
+	lowDropped    []int            // Dropped low dice
+	explosions    []ExplosionChain // Extra dice added by exploding dice, kept apart from the rolled dice
+	rerolled      []RerolledDie    // Rerolled faces and their replacement rolls
+	successes     int              // Dice matching the target of a success counting DiceRoll
+	failures      int              // Dice matching the failure of a success counting DiceRoll
+	critHit       bool             // Natural roll in the critical hit range
+	critFail      bool             // Natural roll in the critical fail range
+	critDice      []int            // Dice added by the crit rollAttribute, also part of the dice
+	stages        []StageRecord    // Effect of each RollStage, in order
 }
 
 // DiceRollResult constructor with DiceRoll readable string and rollAttributes.
-func newDiceRollResult(diceRoll DiceRoll) *diceRollResult {
-	return &diceRollResult{diceRoll, []int{}, 0, []int{}, []int{}, []int{}}
+func newDiceRollResult(diceRoll DiceRoll) *DiceRollResult {
+	return &DiceRollResult{diceRoll, []int{}, 0, []int{}, []int{}, []int{}, []ExplosionChain{}, []RerolledDie{}, 0, 0, false, false, []int{}, []StageRecord{}}
 }
 
 // Returns the total sum of a DiceRollResult array.
-func diceRollResultsSum(results ...diceRollResult) (sum int) {
+func diceRollResultsSum(results ...DiceRollResult) (sum int) {
 	for i := range results {
 		sum += results[i].sum
 	}
@@ -31,57 +37,132 @@ func diceRollResultsSum(results ...diceRollResult) (sum int) {
 	return
 }
 
-// Detects a critical hit.
-func (rollResult diceRollResult) hasScoredCritHit() bool {
-	critHit := false
+// Returns true if the natural roll scored a critical hit.
+func (rollResult DiceRollResult) CritHit() bool {
+	return rollResult.critHit
+}
+
+// Returns true if the natural roll scored a critical fail.
+func (rollResult DiceRollResult) CritFail() bool {
+	return rollResult.critFail
+}
+
+// Returns the performed DiceRoll.
+func (result DiceRollResult) DiceRoll() DiceRoll {
+	return result.diceRoll
+}
 
-	if len(rollResult.dice) == 1 && rollResult.dice[0] == 20 &&
-		rollResult.diceRoll.diceAmmount == 1 && rollResult.diceRoll.diceSize == 20 {
-		critHit = true
+// Returns the kept dice, in rolled order. Compounded dice include their extra dice.
+func (result DiceRollResult) Dice() []int {
+	return slices.Clone(result.dice)
+}
+
+// Returns the dice dropped by advantage or disadvantage.
+func (result DiceRollResult) AdvDisDropped() []int {
+	return slices.Clone(result.advDisDropped)
+}
+
+// Returns the dice dropped as highest by keep and drop rules, in rolled order.
+func (result DiceRollResult) HighDropped() []int {
+	return slices.Clone(result.highDropped)
+}
+
+// Returns the dice dropped as lowest by keep and drop rules, in rolled order.
+func (result DiceRollResult) LowDropped() []int {
+	return slices.Clone(result.lowDropped)
+}
+
+// Returns the explosion chains of exploding dice.
+func (result DiceRollResult) Explosions() []ExplosionChain {
+	explosions := make([]ExplosionChain, len(result.explosions))
+	for i := range result.explosions {
+		explosions[i] = ExplosionChain{result.explosions[i].trigger, slices.Clone(result.explosions[i].extra)}
 	}
+	return explosions
+}
+
+// Returns the rerolled dice and their replacement rolls.
+func (result DiceRollResult) Rerolled() []RerolledDie {
+	return slices.Clone(result.rerolled)
+}
+
+// Returns the effect of each RollStage of the RollPipeline, in order.
+func (result DiceRollResult) Stages() []StageRecord {
+	return slices.Clone(result.stages)
+}
+
+// Returns the dice added by a crit, also part of the kept or dropped dice.
+func (result DiceRollResult) CritDice() []int {
+	return slices.Clone(result.critDice)
+}
 
-	return critHit
+// Returns the applied rollAttribute strings, sorted.
+func (result DiceRollResult) Attributes() []string {
+	return result.diceRoll.attributeStrs()
+}
+
+// Returns the DiceRoll modifier.
+func (result DiceRollResult) Modifier() int {
+	return result.diceRoll.modifier
+}
+
+// Returns the DamageType of the DiceRoll.
+func (result DiceRollResult) DamageType() DamageType {
+	return result.diceRoll.damageType()
+}
+
+// Returns the DiceRoll result, net successes for success counting dice pools.
+func (result DiceRollResult) Sum() int {
+	return result.sum
+}
+
+// Returns the dice counted as successes by a success counting dice pool.
+func (result DiceRollResult) Successes() int {
+	return result.successes
+}
+
+// Returns the dice counted as failures by a success counting dice pool.
+func (result DiceRollResult) Failures() int {
+	return result.failures
 }
 
 // Human readable DiceRollResult string.
-func (result diceRollResult) String() string {
+func (result DiceRollResult) String() string {
 	resultStr := " Result of DiceRoll \""
 	advDisStr := ""
-	spell := false
-
-	// Start with roll attributes, add them as String
-	if result.diceRoll.rollAttribs != nil {
-		// Sort the attributes
-		attribs := maps.Keys(result.diceRoll.rollAttribs.attribs)
-
-		sort.SliceStable(attribs, func(i int, j int) bool {
-			return attribs[i] < attribs[j]
-		})
-
-		// Add them to the result string
-		for i := range attribs {
-			rollAttrib := rollAttribute(attribs[i])
-			// Collect that affects the results printout later
-			switch rollAttrib {
-			case advantageAttrib:
-				advDisStr = "Adv drop:"
-			case disadvantageAttrib:
-				advDisStr = "Dis drop:"
-			case spellAttrib:
-				spell = true
-			}
-			resultStr += fmt.Sprintf("%s ", rollAttributeMapKey(rollAttributeMap, rollAttrib))
-		}
+
+	// Collect roll attributes affecting the results printout
+	switch {
+	case result.diceRoll.hasAttrib(AdvantageAttrib):
+		advDisStr = "Adv drop:"
+	case result.diceRoll.hasAttrib(DisadvantageAttrib):
+		advDisStr = "Dis drop:"
 	}
+	spell := result.diceRoll.hasAttrib(SpellAttrib)
 
-	// DiceRoll string and dice result array
+	// DiceRoll string, roll attributes included, and dice result array
 	resultStr += fmt.Sprintf("%s\": \n  Rolls:     %s\n", result.diceRoll, fmt.Sprint(result.dice))
 
+	// Dice added by crits
+	if len(result.critDice) > 0 {
+		resultStr += fmt.Sprintf("  Crit dice: %s\n", fmt.Sprint(result.critDice))
+	}
+
 	// Advantage / disadvantage dropped dice array
 	if len(result.advDisDropped) > 0 {
 		resultStr += fmt.Sprintf("  %s  %s\n", advDisStr, fmt.Sprint(result.advDisDropped))
 	}
 
+	// Rerolled dice
+	if len(result.rerolled) > 0 {
+		resultStr += fmt.Sprintf("  Rerolled:  %s\n", fmt.Sprint(result.rerolled))
+	}
+
+	// Exploding dice chains
+	if len(result.explosions) > 0 {
+		resultStr += fmt.Sprintf("  Exploded:  %s\n", fmt.Sprint(result.explosions))
+	}
+
 	// Dropped High dice array
 	if len(result.highDropped) > 0 {
 		resultStr += fmt.Sprintf("  Drop High: %s\n", fmt.Sprint(result.highDropped))
@@ -92,12 +173,24 @@ func (result diceRollResult) String() string {
 		resultStr += fmt.Sprintf("  Drop Low:  %s\n", fmt.Sprint(result.lowDropped))
 	}
 
-	// The DiceRoll sum
-	resultStr += fmt.Sprintf("  Sum:       %d", result.sum)
+	if result.diceRoll.successRule() != nil {
+		// Success counting dice pools have their own layout
+		resultStr += successesString(result)
+	} else {
+		// The DiceRoll sum
+		resultStr += fmt.Sprintf("  Sum:       %d", result.sum)
+
+		// Half ammount for passing a spell saving throw
+		if spell {
+			resultStr += fmt.Sprintf(" Saved: %d", halve(result.sum))
+		}
+	}
 
-	// Half ammount for passing a spell saving throw
-	if spell {
-		resultStr += fmt.Sprintf(" Saved: %d", halve(result.sum))
+	// Critical hits and fails
+	if result.critHit {
+		resultStr += " Critical hit!"
+	} else if result.critFail {
+		resultStr += " Critical fail!"
 	}
 
 	resultStr += "\n"
@@ -8,7 +8,7 @@ import (
 func FuzzDiceRollResultSum(f *testing.F) {
 	f.Add(10)
 	f.Fuzz(func(t *testing.T, rolls int) {
-		results := make([]diceRollResult, 0)
+		results := make([]DiceRollResult, 0)
 		for i := 0; i < rolls; i++ {
 			diceRoll := newDiceRoll(rand.Intn(99999)+1, rand.Intn(99999)+1, rand.Intn(99999)+1)
 			result, _ := validateAndperformRoll(*diceRoll)
@@ -18,3 +18,31 @@ func FuzzDiceRollResultSum(f *testing.F) {
 		diceRollResultsSum(results...)
 	})
 }
+
+func TestDiceRollResultAccessors(t *testing.T) {
+	// Max source rolls the highest face of every die
+	results, argErrs := NewRoller(maxSource{}).PerformRollArgs("adv", "4d6dl1+2", "fire")
+	if len(argErrs) > 0 {
+		t.Fatalf("Unexpected errors: %v", argErrs)
+	}
+	result := results[0].Results()[0]
+
+	if diceRoll := result.DiceRoll(); diceRoll.DiceAmmount() != 4 || diceRoll.DiceSize() != 6 || diceRoll.Modifier() != 2 {
+		t.Fatalf("DiceRoll accessors returned %dd%d+%d", diceRoll.DiceAmmount(), diceRoll.DiceSize(), diceRoll.Modifier())
+	}
+	if len(result.Dice()) != 3 || len(result.LowDropped()) != 1 || result.Sum() != 20 || result.Modifier() != 2 {
+		t.Fatalf("Unexpected accessors for %s", result)
+	}
+	if result.DamageType() != DamageFire {
+		t.Fatalf("Damage type is %s, wanted fire", result.DamageType())
+	}
+	if attribs := result.Attributes(); len(attribs) != 1 || attribs[0] != "adv" {
+		t.Fatalf("Attributes are %v, wanted [adv]", attribs)
+	}
+
+	// Returned slices are copies, modifying them leaves the result untouched
+	result.Dice()[0] = 0
+	if result.Dice()[0] != 6 {
+		t.Fatal("Modifying returned dice modified the result")
+	}
+}
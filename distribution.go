@@ -0,0 +1,558 @@
+package diceroller
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Max amount of consecutive values a Distribution can hold, to avoid huge memory use.
+const maxDistributionSupport int = 1 << 20
+
+// Max amount of steps computing keep and drop rules, to avoid long run times.
+const maxDistributionSteps int = 1 << 24
+
+var errDistributionTooLarge = errors.New("distribution too large to compute exactly, use Roller.Simulate instead")
+
+// A Distribution is the exact probability distribution of the outcome of a DiceRoll or RollArgs, computed without sampling.
+type Distribution struct {
+	dist pmf
+}
+
+// Computes the exact Distribution of a DiceRoll result with the default Roller settings.
+// Returns an error if the DiceRoll is invalid or too large.
+func DiceRollDistribution(diceRoll DiceRoll) (*Distribution, error) {
+	return defaultRoller.DiceRollDistribution(diceRoll)
+}
+
+// Computes the exact Distribution of the total sum of RollArgs with the default Roller settings.
+// Invalid RollArgs are worth 0 and return an error, like PerformRollArgsAndSum.
+func RollArgsDistribution(rollArgs ...string) (*Distribution, []error) {
+	return defaultRoller.RollArgsDistribution(rollArgs...)
+}
+
+// Computes the exact Distribution of a DiceRoll result rolled by the Roller, critical settings included.
+// Returns an error if the DiceRoll is invalid or too large.
+func (roller *Roller) DiceRollDistribution(diceRoll DiceRoll) (*Distribution, error) {
+	total, _, err := roller.diceRollPMF(diceRoll)
+	if err != nil {
+		return nil, err
+	}
+	return &Distribution{total.trim()}, nil
+}
+
+// Computes the exact Distribution of the total sum of RollArgs rolled by the Roller, critical hits
+// propagation and settings included. Invalid RollArgs are worth 0 and return an error, like PerformRollArgsAndSum.
+func (roller *Roller) RollArgsDistribution(rollArgs ...string) (*Distribution, []error) {
+	rollExprs, argErrs := parseRollArgs(rollArgs...)
+	total, distErrs := roller.rollingExpressionsPMF(rollExprs...)
+	return &Distribution{total.trim()}, append(argErrs, distErrs...)
+}
+
+// Returns the lowest possible outcome.
+func (distribution Distribution) Min() int {
+	return distribution.dist.min
+}
+
+// Returns the highest possible outcome.
+func (distribution Distribution) Max() int {
+	return distribution.dist.max()
+}
+
+// Returns the expected outcome.
+func (distribution Distribution) Mean() (mean float64) {
+	for i := range distribution.dist.p {
+		mean += float64(distribution.dist.min+i) * distribution.dist.p[i]
+	}
+	return
+}
+
+// Returns the variance of the outcome.
+func (distribution Distribution) Variance() (variance float64) {
+	mean := distribution.Mean()
+	for i := range distribution.dist.p {
+		deviation := float64(distribution.dist.min+i) - mean
+		variance += deviation * deviation * distribution.dist.p[i]
+	}
+	return
+}
+
+// Returns the standard deviation of the outcome.
+func (distribution Distribution) StdDev() float64 {
+	return math.Sqrt(distribution.Variance())
+}
+
+// Returns the probability of every possible outcome.
+func (distribution Distribution) PMF() map[int]float64 {
+	probabilities := make(map[int]float64)
+	for i := range distribution.dist.p {
+		if distribution.dist.p[i] > 0 {
+			probabilities[distribution.dist.min+i] = distribution.dist.p[i]
+		}
+	}
+	return probabilities
+}
+
+// Returns the probability of the outcome being value.
+func (distribution Distribution) Probability(value int) float64 {
+	return distribution.dist.probability(value)
+}
+
+// Returns the probability of the outcome being value or more, P(X >= value).
+func (distribution Distribution) AtLeast(value int) (probability float64) {
+	for i := max(value-distribution.dist.min, 0); i < len(distribution.dist.p); i++ {
+		probability += distribution.dist.p[i]
+	}
+	return min(probability, 1)
+}
+
+// Returns the probability of the outcome being value or less, P(X <= value).
+func (distribution Distribution) AtMost(value int) float64 {
+	return max(1-distribution.AtLeast(value+1), 0)
+}
+
+// Human readable Distribution string.
+func (distribution Distribution) String() string {
+	return fmt.Sprintf("Min: %d Max: %d Mean: %.3f StdDev: %.3f", distribution.Min(), distribution.Max(), distribution.Mean(), distribution.StdDev())
+}
+
+// Computes the pmf of the total sum of rollingExpressions. A critical hit scored by an attack expression
+// applies the crit rollAttribute to the damage expressions it owns, like performRollingExpressions.
+func (roller *Roller) rollingExpressionsPMF(rollExprs ...rollingExpression) (pmf, []error) {
+	// Total sum so far, split on whether the current attack scored a critical hit
+	noCritSoFar, critSoFar := pointPMF(0), pmf{}
+	var distErrs []error
+
+	for e := range rollExprs {
+		total, crit, exprErrs := roller.rollingExpressionPMF(rollExprs[e], false)
+		distErrs = append(distErrs, exprErrs...)
+
+		switch rollExprs[e].kind {
+		case attackExpression:
+			soFar := noCritSoFar.add(critSoFar)
+			noCritSoFar, critSoFar = convolve(soFar, total.subtract(crit)), convolve(soFar, crit)
+		case damageExpression:
+			noCritSoFar = convolve(noCritSoFar, total)
+			if critSoFar.total() > 0 {
+				critTotal, _, _ := roller.rollingExpressionPMF(rollExprs[e], true)
+				critSoFar = convolve(critSoFar, critTotal)
+			}
+		default:
+			noCritSoFar, critSoFar = convolve(noCritSoFar.add(critSoFar), total), pmf{}
+		}
+
+		noCritSoFar, critSoFar = noCritSoFar.trim(), critSoFar.trim()
+	}
+
+	return noCritSoFar.add(critSoFar), distErrs
+}
+
+// Computes the pmf of a rollingExpression sum and the sub-distribution of the outcomes scoring a critical hit.
+// Invalid DiceRolls and rollFormulas are worth 0 and return an error.
+func (roller *Roller) rollingExpressionPMF(rollExpr rollingExpression, critHit bool) (total pmf, crit pmf, distErrs []error) {
+	total, noCrit := pointPMF(0), pointPMF(0)
+
+	for i := range rollExpr.diceRolls {
+		diceRoll := rollExpr.diceRolls[i]
+		if critHit {
+			diceRoll = diceRoll.withAttrib(CritAttrib)
+		}
+
+		diceTotal, diceCrit, err := roller.diceRollPMF(diceRoll)
+		if err != nil {
+			distErrs = append(distErrs, err)
+			continue
+		}
+		total, noCrit = convolve(total, diceTotal), convolve(noCrit, diceTotal.subtract(diceCrit))
+	}
+
+	for f := range rollExpr.formulas {
+		formulaTotal, formulaNoCrit, err := roller.formulaPMF(rollExpr.formulas[f], critHit)
+		if err != nil {
+			distErrs = append(distErrs, err)
+			continue
+		}
+		total, noCrit = convolve(total, formulaTotal), convolve(noCrit, formulaNoCrit)
+	}
+
+	return total, total.subtract(noCrit), distErrs
+}
+
+// Computes the pmf of a rollFormula and the sub-distribution of the outcomes without critical hit.
+func (roller *Roller) formulaPMF(formula rollFormula, critHit bool) (total pmf, noCrit pmf, err error) {
+	totals, noCrits := make([]pmf, len(formula.diceRolls)), make([]pmf, len(formula.diceRolls))
+	for i := range formula.diceRolls {
+		diceRoll := formula.diceRolls[i]
+		if critHit {
+			diceRoll = diceRoll.withAttrib(CritAttrib)
+		}
+
+		diceTotal, diceCrit, err := roller.diceRollPMF(diceRoll)
+		if err != nil {
+			return pmf{}, pmf{}, fmt.Errorf("%s: %w", formula.rollArg, err)
+		}
+		totals[i], noCrits[i] = diceTotal, diceTotal.subtract(diceCrit)
+	}
+
+	// Formula operations are bilinear, evaluating the sub-distributions gives the outcomes without critical hit
+	if total, err = formula.root.evaluatePMF(totals); err == nil {
+		noCrit, err = formula.root.evaluatePMF(noCrits)
+	}
+	if err != nil {
+		return pmf{}, pmf{}, fmt.Errorf("%s: %w", formula.rollArg, err)
+	}
+	return total, noCrit, nil
+}
+
+// Evaluates the node using the pmfs of the formula DiceRolls.
+func (node *formulaNode) evaluatePMF(diceRollPMFs []pmf) (pmf, error) {
+	switch node.kind {
+	case numberToken:
+		return pointPMF(node.value), nil
+	case diceToken:
+		return diceRollPMFs[node.value], nil
+	}
+
+	right, err := node.right.evaluatePMF(diceRollPMFs)
+	if err != nil {
+		return pmf{}, err
+	}
+
+	// Unary minus
+	if node.left == nil {
+		return right.transform(func(value int) int { return -value }), nil
+	}
+
+	left, err := node.left.evaluatePMF(diceRollPMFs)
+	if err != nil {
+		return pmf{}, err
+	}
+
+	switch node.kind {
+	case plusToken:
+		return convolve(left, right), nil
+	case minusToken:
+		return convolve(left, right.transform(func(value int) int { return -value })), nil
+	case timesToken:
+		return combine(left, right, func(value int, value2 int) (int, error) { return value * value2, nil })
+	}
+
+	return combine(left, right, func(value int, value2 int) (int, error) {
+		if value2 == 0 {
+			return 0, fmt.Errorf("division by zero")
+		}
+		return floorDivide(value, value2), nil
+	})
+}
+
+// Computes the pmf of a DiceRoll result and the sub-distribution of the outcomes scoring a critical hit.
+func (roller *Roller) diceRollPMF(diceRoll DiceRoll) (total pmf, crit pmf, err error) {
+	if err := validateDiceRoll(diceRoll); err != nil {
+		return pmf{}, pmf{}, err
+	}
+
+	if roller.pipeline != nil {
+		return pmf{}, pmf{}, fmt.Errorf("%s: custom RollPipelines can't be computed exactly, use Roller.Simulate instead", diceRoll)
+	}
+	if len(diceRoll.attributeHooks()) > 0 {
+		return pmf{}, pmf{}, fmt.Errorf("%s: custom rollAttribute hooks can't be computed exactly, use Roller.Simulate instead", diceRoll)
+	}
+
+	die, err := newDieDistribution(diceRoll)
+	if err != nil {
+		return pmf{}, pmf{}, fmt.Errorf("%s: %w", diceRoll, err)
+	}
+
+	// Actual dice ammount and crit dice added at their max roll
+	diceAmmount, maxDiceAmmount := diceRoll.diceAmmount, 0
+	if diceRoll.hasAttrib(CritAttrib) {
+		diceAmmount += roller.critDamage.rolledDice(diceRoll.diceAmmount)
+		maxDiceAmmount = roller.critDamage.maxDice(diceRoll.diceAmmount)
+	}
+	if maxDiceAmmount > 0 && len(diceRoll.keepDropRules()) > 0 {
+		return pmf{}, pmf{}, fmt.Errorf("%s: max plus roll crit dice can't be kept or dropped exactly, use Roller.Simulate instead", diceRoll)
+	}
+
+	// Dice dropped by keep and drop rules
+	high, low := 0, 0
+	for _, rule := range diceRoll.keepDropRules() {
+		ruleHigh, ruleLow := rule.dropCounts(diceAmmount - high - low)
+		high, low = high+ruleHigh, low+ruleLow
+	}
+
+	if total, err = die.sumOfDice(diceAmmount, high, low, nil); err != nil {
+		return pmf{}, pmf{}, fmt.Errorf("%s: %w", diceRoll, err)
+	}
+	maxDiceSum := maxDiceAmmount * diceScore(diceRoll)(diceRoll.diceSize)
+	total = total.transform(func(sum int) int { return roller.applySumRules(diceRoll, sum+maxDiceSum) })
+
+	// Critical hits are scored by a single kept d20, as in Roller.detectCrits
+	if diceAmmount-high-low == 1 && maxDiceAmmount == 0 && diceRoll.diceSize == critDiceSize && diceRoll.successRule() == nil {
+		crits := roller.critRangeOf(diceRoll)
+		crit, _ = die.sumOfDice(diceAmmount, high, low, crits.isCritHit)
+		crit = crit.transform(func(sum int) int { return roller.applySumRules(diceRoll, sum) })
+	}
+
+	return total, crit, nil
+}
+
+// Distribution of a single die of a DiceRoll, once rerolled, advantage applied and exploded.
+type dieDistribution struct {
+	values        []int     // Die values ranking dice for keep and drop rules, descending
+	natural       []int     // Natural face rolled for each value, before compounding
+	probabilities []float64 // Probability of each value
+	kept          []int     // Contribution of each value to the sum when the die is kept
+	bonus         []pmf     // Contribution of each value to the sum from extra exploding dice, when the die is kept
+}
+
+// Computes the dieDistribution of diceRoll.
+func newDieDistribution(diceRoll DiceRoll) (*dieDistribution, error) {
+	faces := faceProbabilities(diceRoll)
+
+	score := diceScore(diceRoll)
+
+	die := &dieDistribution{make([]int, 0), make([]int, 0), make([]float64, 0), make([]int, 0), make([]pmf, 0)}
+	rule := diceRoll.explodeRule()
+	if rule == nil {
+		for face := range faces {
+			die.addValue(face, face, faces[face], pointPMF(0))
+		}
+	} else if rule.mode == explodeCompound {
+		if (maxExplodeChainLength+1)*diceRoll.diceSize >= maxDistributionSupport {
+			return nil, errDistributionTooLarge
+		}
+
+		// Compounded dice values include their whole chain, each natural face apart for critical hits
+		chain := explosionChainPMF(diceRoll.diceSize, *rule, func(extra int) int { return extra })
+		for face := range faces {
+			if !rule.explodes(face, diceRoll.diceSize) {
+				die.addValue(face, face, faces[face], pointPMF(0))
+				continue
+			}
+			compounded := chain.shift(face).scale(faces[face])
+			for i := range compounded.p {
+				if compounded.p[i] > 0 {
+					die.addValue(compounded.min+i, face, compounded.p[i], pointPMF(0))
+				}
+			}
+		}
+	} else {
+		if (maxExplodeChainLength+1)*diceRoll.diceSize >= maxDistributionSupport {
+			return nil, errDistributionTooLarge
+		}
+
+		// Extra dice are added to the sum apart from the die
+		chain := explosionChainPMF(diceRoll.diceSize, *rule, score)
+		for face := range faces {
+			if rule.explodes(face, diceRoll.diceSize) {
+				die.addValue(face, face, faces[face], chain)
+			} else {
+				die.addValue(face, face, faces[face], pointPMF(0))
+			}
+		}
+	}
+
+	for i := range die.values {
+		die.kept = append(die.kept, score(die.values[i]))
+	}
+	die.sortDescending()
+
+	return die, nil
+}
+
+// Adds a die value rolled from a natural face with probability, and its extra exploding dice bonus.
+func (die *dieDistribution) addValue(value int, natural int, probability float64, bonus pmf) {
+	die.values, die.natural = append(die.values, value), append(die.natural, natural)
+	die.probabilities, die.bonus = append(die.probabilities, probability), append(die.bonus, bonus)
+}
+
+// Returns the contribution of a die value to the sum of diceRoll. Dice pools count successes and failures instead of summing.
+func diceScore(diceRoll DiceRoll) func(value int) int {
+	rule := diceRoll.successRule()
+	if rule == nil {
+		return func(value int) int { return value }
+	}
+	return func(value int) int {
+		if rule.target.matches(value) {
+			return 1
+		} else if rule.failure != nil && rule.failure.matches(value) {
+			return -1
+		}
+		return 0
+	}
+}
+
+// Computes the probability of each face of a diceRoll die after rerolls and advantage, indexed by face.
+func faceProbabilities(diceRoll DiceRoll) map[int]float64 {
+	diceSize := diceRoll.diceSize
+	uniform := 1 / float64(diceSize)
+
+	// Rerolled die
+	rerolled := make([]float64, diceSize+1)
+	rule := diceRoll.rerollRule()
+	rerollChance := 0.0
+	if rule != nil {
+		rerollChance = float64(rule.threshold.matchingFaces(diceSize)) * uniform
+	}
+	for face := 1; face <= diceSize; face++ {
+		switch {
+		case rule == nil:
+			rerolled[face] = uniform
+		case rule.once && rule.threshold.matches(face):
+			rerolled[face] = rerollChance * uniform
+		case rule.once:
+			rerolled[face] = uniform + rerollChance*uniform
+		case rule.threshold.matches(face):
+			// Only kept when every roll up to the reroll cap matched
+			rerolled[face] = math.Pow(rerollChance, float64(maxRerollChainLength)) * uniform
+		default:
+			// Geometric series of rerolls up to the reroll cap
+			series := 0.0
+			for rerolls := 0; rerolls <= maxRerollChainLength; rerolls++ {
+				series += math.Pow(rerollChance, float64(rerolls))
+			}
+			rerolled[face] = series * uniform
+		}
+	}
+
+	// Advantage keeps the max of two rolls, disadvantage the min
+	faces := make(map[int]float64)
+	atMost, atLeast := 0.0, 1.0
+	for face := 1; face <= diceSize; face++ {
+		probability := rerolled[face]
+		switch {
+		case diceRoll.hasAttrib(AdvantageAttrib):
+			probability = math.Pow(atMost+rerolled[face], 2) - math.Pow(atMost, 2)
+		case diceRoll.hasAttrib(DisadvantageAttrib):
+			probability = math.Pow(atLeast, 2) - math.Pow(atLeast-rerolled[face], 2)
+		}
+		atMost, atLeast = atMost+rerolled[face], atLeast-rerolled[face]
+
+		if probability > 0 {
+			faces[face] = probability
+		}
+	}
+
+	return faces
+}
+
+// Computes the pmf of the extra dice added by an exploding die, valued using value.
+// Extra dice are rolled as long as they explode, up to the explosion cap.
+func explosionChainPMF(diceSize int, rule explodeRule, value func(extra int) int) pmf {
+	uniform := 1 / float64(diceSize)
+
+	// Extra die worth, split on whether it explodes again
+	stops, explodes := pmf{}, pmf{}
+	for extraRoll := 1; extraRoll <= diceSize; extraRoll++ {
+		extra := extraRoll
+		if rule.mode == explodePenetrate {
+			extra--
+		}
+		if rule.explodes(extraRoll, diceSize) {
+			explodes = explodes.add(pointPMF(value(extra)).scale(uniform))
+		} else {
+			stops = stops.add(pointPMF(value(extra)).scale(uniform))
+		}
+	}
+
+	// Chain with no extra die left before the cap, then one more each step
+	chain := pointPMF(0)
+	for extras := 1; extras <= maxExplodeChainLength; extras++ {
+		chain = stops.add(convolve(explodes, chain)).trim()
+	}
+	return chain
+}
+
+// Sorts values in descending order, along with their probabilities and contributions.
+func (die *dieDistribution) sortDescending() {
+	order := make([]int, len(die.values))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(i int, j int) int { return die.values[j] - die.values[i] })
+
+	sorted := dieDistribution{make([]int, len(order)), make([]int, len(order)), make([]float64, len(order)), make([]int, len(order)), make([]pmf, len(order))}
+	for i := range order {
+		sorted.values[i], sorted.natural[i], sorted.probabilities[i] = die.values[order[i]], die.natural[order[i]], die.probabilities[order[i]]
+		sorted.kept[i], sorted.bonus[i] = die.kept[order[i]], die.bonus[order[i]]
+	}
+	*die = sorted
+}
+
+// Returns the pmf of a single die contribution to the sum when kept.
+func (die dieDistribution) keptPMF() pmf {
+	kept := pmf{}
+	for i := range die.values {
+		kept = kept.add(die.bonus[i].shift(die.kept[i]).scale(die.probabilities[i]))
+	}
+	return kept
+}
+
+// Computes the pmf of the sum of diceAmmount dice, dropping the high highest and low lowest dice along with
+// their extra exploding dice. A non nil keptFilter restricts it to the sub-distribution where every kept die
+// natural face matches.
+func (die dieDistribution) sumOfDice(diceAmmount int, high int, low int, keptFilter func(natural int) bool) (pmf, error) {
+	if width := len(die.keptPMF().p) * diceAmmount; width >= maxDistributionSupport {
+		return pmf{}, errDistributionTooLarge
+	}
+
+	// Without drops, the sum of independent dice
+	if high == 0 && low == 0 && keptFilter == nil {
+		return die.keptPMF().convolvePower(diceAmmount), nil
+	}
+
+	if steps := len(die.values) * diceAmmount * diceAmmount; steps >= maxDistributionSteps {
+		return pmf{}, errDistributionTooLarge
+	}
+
+	// Order statistics: values are assigned from highest to lowest, sums[c] being the pmf of the
+	// sum when c dice are assigned. Dice ranked in [high, diceAmmount-low) are kept.
+	sums := make([]pmf, diceAmmount+1)
+	sums[0] = pointPMF(0)
+	for v := range die.values {
+		logProbability := math.Log(die.probabilities[v])
+		bonusPowers := []pmf{pointPMF(0)}
+		next := make([]pmf, diceAmmount+1)
+
+		for assigned := 0; assigned <= diceAmmount; assigned++ {
+			if len(sums[assigned].p) == 0 {
+				continue
+			}
+			for j := 0; assigned+j <= diceAmmount; j++ {
+
+				// Ways to pick j of the remaining dice rolling this value
+				weight := 1.0
+				if j > 0 {
+					weight = math.Exp(logChoose(diceAmmount-assigned, j) + float64(j)*logProbability)
+				}
+				if weight == 0 {
+					continue
+				}
+
+				keptDice := max(0, min(assigned+j, diceAmmount-low)-max(assigned, high))
+				if keptDice > 0 && keptFilter != nil && !keptFilter(die.natural[v]) {
+					continue
+				}
+
+				// Only kept dice add their extra exploding dice
+				for len(bonusPowers) <= keptDice {
+					bonusPowers = append(bonusPowers, convolve(bonusPowers[len(bonusPowers)-1], die.bonus[v]))
+				}
+				contribution := convolve(sums[assigned], bonusPowers[keptDice]).scale(weight).shift(keptDice * die.kept[v])
+				next[assigned+j] = next[assigned+j].add(contribution)
+			}
+		}
+		sums = next
+	}
+
+	return sums[diceAmmount], nil
+}
+
+// Returns the natural logarithm of the binomial coefficient n choose k.
+func logChoose(n int, k int) float64 {
+	lgammaN, _ := math.Lgamma(float64(n + 1))
+	lgammaK, _ := math.Lgamma(float64(k + 1))
+	lgammaNK, _ := math.Lgamma(float64(n - k + 1))
+	return lgammaN - lgammaK - lgammaNK
+}
@@ -0,0 +1,160 @@
+package diceroller
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+// Roll Args and their expected mean
+var distributionMeans = []struct {
+	rollArgs []string
+	mean     float64
+}{
+	{[]string{"1d6"}, 3.5},
+	{[]string{"2d6+3"}, 10},
+	{[]string{"adv", "1d20"}, 13.825},
+	{[]string{"dis", "1d20"}, 7.175},
+	{[]string{"4d6kh3"}, 15869.0 / 1296},
+	{[]string{"1d2-4"}, 1},
+	{[]string{"crit", "1d6"}, 7},
+	{[]string{"minus", "1d6"}, -3.5},
+	{[]string{"10d10>=8"}, 3},
+	{[]string{"1d20ro1"}, 10.975},
+	{[]string{"1d6!"}, 4.2},
+	{[]string{"hit", "1d20", "dmg", "1d6"}, 14.175},
+	{[]string{"(1d4)*2"}, 5}}
+
+// Roll Args checked against simulated rolls
+var distributionSimulatedRollArgs = [][]string{
+	{"4d6r<3kh3"},
+	{"6d10!10>=8"},
+	{"3d6!p"},
+	{"2d6!!"},
+	{"droplow", "8d6kh3+2"},
+	{"half", "3d6-4"},
+	{"5d10>7f<=2+1"},
+	{"(1d4+1)*2", "1d10/2"},
+	{"hit", "1d20", "dmg", "2d6+1d8"},
+	{"4d6!kl1"},
+	{"3d6!pdh1"}}
+
+// Exploding Roll Args checked against Simulate
+var distributionExplodingRollArgs = [][]string{
+	{"4d6!kl1"},
+	{"4d6!kh1"},
+	{"4d6!!dl2"},
+	{"hit", "1d20!!cs20", "dmg", "10d8"},
+	{"hit", "1d20!>=19cs>=19", "dmg", "2d6"}}
+
+func TestDistributionMeans(t *testing.T) {
+	for i := range distributionMeans {
+		distribution, distErrs := RollArgsDistribution(distributionMeans[i].rollArgs...)
+		if len(distErrs) > 0 {
+			t.Fatalf("Roll Args %v distribution errors: %v", distributionMeans[i].rollArgs, distErrs)
+		}
+		if math.Abs(distribution.Mean()-distributionMeans[i].mean) > 1e-9 {
+			t.Fatalf("Roll Args %v mean is %f, expected %f", distributionMeans[i].rollArgs, distribution.Mean(), distributionMeans[i].mean)
+		}
+		total := 0.0
+		for _, probability := range distribution.PMF() {
+			total += probability
+		}
+		if math.Abs(total-1) > 1e-9 {
+			t.Fatalf("Roll Args %v probabilities add up to %f", distributionMeans[i].rollArgs, total)
+		}
+	}
+}
+
+func TestDistributionProbabilities(t *testing.T) {
+	distribution, _ := RollArgsDistribution("2d6")
+	if distribution.Min() != 2 || distribution.Max() != 12 {
+		t.Fatalf("2d6 distribution ranges from %d to %d", distribution.Min(), distribution.Max())
+	}
+	if math.Abs(distribution.Probability(7)-1.0/6) > 1e-12 {
+		t.Fatalf("2d6 probability of 7 is %f", distribution.Probability(7))
+	}
+	if math.Abs(distribution.AtLeast(10)-6.0/36) > 1e-12 || math.Abs(distribution.AtMost(4)-6.0/36) > 1e-12 {
+		t.Fatalf("2d6 P(X >= 10) is %f, P(X <= 4) is %f", distribution.AtLeast(10), distribution.AtMost(4))
+	}
+	if math.Abs(distribution.Variance()-35.0/6) > 1e-9 {
+		t.Fatalf("2d6 variance is %f", distribution.Variance())
+	}
+
+	// Crit DiceRolls roll twice the dice
+	crit, _ := RollArgsDistribution("crit", "1d6")
+	for value := 2; value <= 12; value++ {
+		if math.Abs(crit.Probability(value)-distribution.Probability(value)) > 1e-12 {
+			t.Fatalf("crit 1d6 probability of %d is %f, expected %f", value, crit.Probability(value), distribution.Probability(value))
+		}
+	}
+}
+
+func TestDiceRollDistribution(t *testing.T) {
+	diceRoll, _ := parseRollArg("3d8+2")
+	distribution, distErr := DiceRollDistribution(*diceRoll)
+	if distErr != nil {
+		t.Fatalf("DiceRoll %s distribution error: %s", diceRoll, distErr.Error())
+	}
+	if distribution.Mean() != 15.5 || distribution.Min() != 5 || distribution.Max() != 26 {
+		t.Fatalf("DiceRoll %s distribution is %s", diceRoll, distribution)
+	}
+
+	if _, distErr := DiceRollDistribution(DiceRoll{0, 6, 0, newRollAttributes()}); distErr == nil {
+		t.Fatal("Invalid DiceRoll distribution returned no error")
+	}
+	if _, distErr := DiceRollDistribution(DiceRoll{1000, 10000, 0, newRollAttributes()}); distErr == nil {
+		t.Fatal("Huge DiceRoll distribution returned no error")
+	}
+}
+
+func TestDistributionErrors(t *testing.T) {
+	if _, distErrs := RollArgsDistribution("1d6/(1d2-1)"); len(distErrs) == 0 {
+		t.Fatal("Division by zero distribution returned no error")
+	}
+	if distribution, distErrs := RollArgsDistribution("1d6", "0d6"); len(distErrs) == 0 || distribution.Mean() != 3.5 {
+		t.Fatal("Invalid RollArg distribution returned no error or wasn't worth 0")
+	}
+}
+
+func TestDistributionMatchesSimulation(t *testing.T) {
+	const rolls = 20000
+	roller := NewPCGRoller(1, 2)
+
+	for i := range distributionSimulatedRollArgs {
+		rollArgs := distributionSimulatedRollArgs[i]
+		distribution, distErrs := RollArgsDistribution(rollArgs...)
+		if len(distErrs) > 0 {
+			t.Fatalf("Roll Args %v distribution errors: %v", rollArgs, distErrs)
+		}
+
+		sum := 0
+		for r := 0; r < rolls; r++ {
+			sum += roller.PerformRollArgsAndSum(rollArgs...)
+		}
+		mean := float64(sum) / rolls
+
+		// Sample means fall within 5 standard errors
+		if tolerance := 5 * distribution.StdDev() / math.Sqrt(rolls); math.Abs(mean-distribution.Mean()) > tolerance {
+			t.Fatalf("Roll Args %v simulated mean %f, expected %f", rollArgs, mean, distribution.Mean())
+		}
+	}
+}
+
+func TestDistributionMatchesSimulate(t *testing.T) {
+	for i := range distributionExplodingRollArgs {
+		rollArgs := distributionExplodingRollArgs[i]
+		distribution, distErrs := RollArgsDistribution(rollArgs...)
+		if len(distErrs) > 0 {
+			t.Fatalf("Roll Args %v distribution errors: %v", rollArgs, distErrs)
+		}
+
+		simulation, simErrs := SimulateContext(context.Background(), rollArgs, 50000, SimulationOptions{Seed: 3})
+		if len(simErrs) > 0 {
+			t.Fatalf("Roll Args %v simulation errors: %v", rollArgs, simErrs)
+		}
+		if low, high := simulation.Total.MeanConfidenceInterval(0.9999); distribution.Mean() < low || distribution.Mean() > high {
+			t.Fatalf("Roll Args %v exact mean %f out of simulated confidence interval %f-%f", rollArgs, distribution.Mean(), low, high)
+		}
+	}
+}
@@ -6,10 +6,25 @@ package main
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/skyestalimit/diceroller"
 )
 
+// Armor Class flag, resolves "hit" RollArgs as attacks against it.
+const acFlag string = "--ac"
+
+// Explain flag, prints how each sum was computed.
+const explainFlag string = "--explain"
+
+// Command line options other than rollArgs.
+type options struct {
+	ac      int  // Armor Class to resolve attacks against
+	hasAC   bool // True if the Armor Class flag was given
+	explain bool // True if the explain flag was given
+}
+
 func main() {
 	// Validate rollArgs have been captured
 	if len(os.Args) < 2 {
@@ -18,8 +33,18 @@ func main() {
 		return
 	}
 
-	// Captured rollArgs
-	rollArgs := os.Args[1:len(os.Args)]
+	// Captured rollArgs and flags
+	rollArgs, opts, flagErr := parseFlags(os.Args[1:len(os.Args)])
+	if flagErr != nil {
+		fmt.Println(flagErr)
+		printUsage()
+		return
+	}
+
+	if opts.hasAC {
+		resolveAttacks(opts.ac, rollArgs, opts.explain)
+		return
+	}
 
 	// Roll!
 	results, errs := diceroller.PerformRollArgs(rollArgs...)
@@ -32,12 +57,81 @@ func main() {
 	// Print results
 	for i := range results {
 		fmt.Println(results[i].String())
+		if opts.explain {
+			fmt.Println(results[i].ExplainText())
+		}
 	}
 
 	// Print total sum
 	fmt.Println("Total sum:", diceroller.RollResultsSum(results...))
 }
 
+// Resolves attacks against ac and prints their outcome, and how each sum was computed if explain.
+func resolveAttacks(ac int, rollArgs []string, explain bool) {
+	resolutions, errs := diceroller.ResolveAttacks(ac, rollArgs...)
+
+	// Print out parsing errors
+	for i := range errs {
+		fmt.Println(errs[i])
+	}
+
+	// Print outcomes
+	damage := 0
+	for i := range resolutions {
+		fmt.Println(resolutions[i].String())
+		if explain {
+			printAttackExplanation(resolutions[i].Attack())
+		}
+		damage += resolutions[i].DamageSum()
+	}
+
+	// Print total damage
+	fmt.Println("Total damage:", damage)
+}
+
+// Prints how the attack roll and damage sums were computed.
+func printAttackExplanation(attack diceroller.AttackResult) {
+	if hit, found := attack.HitResult(); found {
+		fmt.Println(hit.ExplainText())
+	}
+	damage := attack.DamageResults()
+	for i := range damage {
+		fmt.Println(damage[i].ExplainText())
+	}
+}
+
+// Separates flags from rollArgs. Returns the rollArgs, the options and an error if a flag is invalid.
+func parseFlags(args []string) (rollArgs []string, opts options, flagErr error) {
+	for i := 0; i < len(args); i++ {
+		if args[i] == explainFlag {
+			opts.explain = true
+			continue
+		}
+		if args[i] != acFlag && !strings.HasPrefix(args[i], acFlag+"=") {
+			rollArgs = append(rollArgs, args[i])
+			continue
+		}
+
+		// Flag value either follows "=" or is the next argument
+		acStr, found := strings.CutPrefix(args[i], acFlag+"=")
+		if !found {
+			if i+1 >= len(args) {
+				return nil, opts, fmt.Errorf("missing %s value", acFlag)
+			}
+			i++
+			acStr = args[i]
+		}
+
+		ac, convErr := strconv.Atoi(acStr)
+		if convErr != nil {
+			return nil, opts, fmt.Errorf("invalid %s value %q", acFlag, acStr)
+		}
+		opts.ac, opts.hasAC = ac, true
+	}
+
+	return rollArgs, opts, nil
+}
+
 func printUsage() {
-	fmt.Println("Usage:	dndhelper [rollArg...]")
+	fmt.Println("Usage:	dndhelper [--ac AC] [--explain] [rollArg...]")
 }
@@ -14,7 +14,8 @@ var validRollArgs = []string{
 	"10000d10000-10000",
 	"1D8-00",
 	"1d100+0",
-	"20d12-9901"}
+	"20d12-9901",
+	"1+8d8+1"}
 
 // Valid Roll Args attribs
 var validRollArgsAttribs = []string{
@@ -41,7 +42,8 @@ var invalidRollArgs = []string{
 	"0d2",
 	"1d0",
 	"1b8",
-	"1+8d8+1"}
+	"(1d8",
+	"1d6+*2"}
 
 // Invalid Roll Args attribs
 var invalidRollArgsAttribs = []string{
@@ -96,3 +98,38 @@ func FuzzParseManyRollArgs(f *testing.F) {
 		main()
 	})
 }
+
+func TestResolveAttacksAgainstAC(t *testing.T) {
+	os.Args = []string{"dndhelper", "--ac", "15", "hit", "1d20+7", "dmg", "1d8+4", "hit", "1d20+7", "dmg", "1d8+4"}
+	main()
+	os.Args = []string{"dndhelper", "hit", "1d20+7", "--ac=15", "dmg", "1d8+4"}
+	main()
+}
+
+func TestExplain(t *testing.T) {
+	os.Args = []string{"dndhelper", "--explain", "half", "4d6dl1-4", "(1d8+2)*2"}
+	main()
+	os.Args = []string{"dndhelper", "--ac", "15", "--explain", "hit", "1d20+7", "dmg", "1d8+4"}
+	main()
+}
+
+func TestParseFlags(t *testing.T) {
+	rollArgs, opts, flagErr := parseFlags([]string{"hit", "1d20+5", "--ac", "16", "dmg", "1d6"})
+	if flagErr != nil || !opts.hasAC || opts.ac != 16 || len(rollArgs) != 4 {
+		t.Fatalf("Parsed flags %v and rollArgs %v, error: %v", opts, rollArgs, flagErr)
+	}
+	if _, opts, _ := parseFlags([]string{"1d20", "-1"}); opts.hasAC {
+		t.Fatal("RollArgs parsed as Armor Class flag")
+	}
+
+	rollArgs, opts, _ = parseFlags([]string{"--explain", "adv", "1d20+5"})
+	if !opts.explain || opts.hasAC || len(rollArgs) != 2 {
+		t.Fatalf("Parsed explain flag %v and rollArgs %v", opts, rollArgs)
+	}
+
+	for _, invalidFlags := range [][]string{{"1d20", "--ac"}, {"--ac", "high"}, {"--ac=", "1d20"}} {
+		if _, _, flagErr := parseFlags(invalidFlags); flagErr == nil {
+			t.Fatalf("Invalid flags %v returned no error", invalidFlags)
+		}
+	}
+}
@@ -0,0 +1,506 @@
+package diceroller
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Version of the JSON and YAML encoding of DiceRolls and results. Decoding rejects other versions.
+const EncodingVersion int = 1
+
+// Encoded DiceRoll.
+type diceRollEncoding struct {
+	Version    int            `json:"version" yaml:"version"`
+	Amount     int            `json:"amount" yaml:"amount"`
+	Size       int            `json:"size" yaml:"size"`
+	Modifier   int            `json:"modifier" yaml:"modifier"`
+	Attributes rollAttributes `json:"attributes" yaml:"attributes"`
+}
+
+// Encoded rollAttributes. Rules use the RollArg syntax, such as "r1" or "dl1".
+type rollAttributesEncoding struct {
+	Flags      []string `json:"flags,omitempty" yaml:"flags,omitempty"`
+	Reroll     string   `json:"reroll,omitempty" yaml:"reroll,omitempty"`
+	Explode    string   `json:"explode,omitempty" yaml:"explode,omitempty"`
+	KeepDrop   []string `json:"keepDrop,omitempty" yaml:"keepDrop,omitempty"`
+	Success    string   `json:"success,omitempty" yaml:"success,omitempty"`
+	Crits      string   `json:"crits,omitempty" yaml:"crits,omitempty"`
+	DamageType string   `json:"damageType,omitempty" yaml:"damageType,omitempty"`
+}
+
+// Encoded DiceRollResult.
+type diceRollResultEncoding struct {
+	Version       int              `json:"version" yaml:"version"`
+	DiceRoll      DiceRoll         `json:"diceRoll" yaml:"diceRoll"`
+	Dice          []int            `json:"dice" yaml:"dice"`
+	Sum           int              `json:"sum" yaml:"sum"`
+	AdvDisDropped []int            `json:"advDisDropped" yaml:"advDisDropped"`
+	HighDropped   []int            `json:"highDropped" yaml:"highDropped"`
+	LowDropped    []int            `json:"lowDropped" yaml:"lowDropped"`
+	Explosions    []ExplosionChain `json:"explosions" yaml:"explosions"`
+	Rerolled      []RerolledDie    `json:"rerolled" yaml:"rerolled"`
+	Successes     int              `json:"successes" yaml:"successes"`
+	Failures      int              `json:"failures" yaml:"failures"`
+	CritHit       bool             `json:"critHit" yaml:"critHit"`
+	CritFail      bool             `json:"critFail" yaml:"critFail"`
+	CritDice      []int            `json:"critDice" yaml:"critDice"`
+	Stages        []StageRecord    `json:"stages,omitempty" yaml:"stages,omitempty"`
+}
+
+// Encoded StageRecord.
+type stageRecordEncoding struct {
+	Stage      string `json:"stage" yaml:"stage"`
+	SumBefore  int    `json:"sumBefore" yaml:"sumBefore"`
+	SumAfter   int    `json:"sumAfter" yaml:"sumAfter"`
+	DiceBefore []int  `json:"diceBefore" yaml:"diceBefore"`
+	DiceAfter  []int  `json:"diceAfter" yaml:"diceAfter"`
+}
+
+// Encoded ExplosionChain.
+type explosionChainEncoding struct {
+	Trigger int   `json:"trigger" yaml:"trigger"`
+	Extra   []int `json:"extra" yaml:"extra"`
+}
+
+// Encoded RerolledDie.
+type rerolledDieEncoding struct {
+	Face        int `json:"face" yaml:"face"`
+	Replacement int `json:"replacement" yaml:"replacement"`
+}
+
+// Encoded FormulaResult. The formula is kept as its RollArg.
+type formulaResultEncoding struct {
+	Formula string           `json:"formula" yaml:"formula"`
+	Results []DiceRollResult `json:"results" yaml:"results"`
+	Sum     int              `json:"sum" yaml:"sum"`
+}
+
+// Encoded RollResult.
+type rollResultEncoding struct {
+	Version        int              `json:"version" yaml:"version"`
+	Kind           string           `json:"kind" yaml:"kind"`
+	Results        []DiceRollResult `json:"results" yaml:"results"`
+	FormulaResults []FormulaResult  `json:"formulaResults" yaml:"formulaResults"`
+	Seed           *RollSeed        `json:"seed,omitempty" yaml:"seed,omitempty"`
+}
+
+// Validates an encoding version. Returns nil if valid, an error if invalid.
+func validateEncodingVersion(version int) error {
+	if version != EncodingVersion {
+		return fmt.Errorf("unsupported encoding version %d, wanted %d", version, EncodingVersion)
+	}
+	return nil
+}
+
+// Returns the encoded DiceRoll.
+func (diceRoll DiceRoll) encode() diceRollEncoding {
+	attribs := newRollAttributes()
+	if diceRoll.rollAttribs != nil {
+		attribs = diceRoll.rollAttribs
+	}
+	return diceRollEncoding{EncodingVersion, diceRoll.diceAmmount, diceRoll.diceSize, diceRoll.modifier, *attribs}
+}
+
+// Decodes and validates an encoded DiceRoll. Returns an error if invalid.
+func (diceRoll *DiceRoll) decode(encoded diceRollEncoding) error {
+	if versionErr := validateEncodingVersion(encoded.Version); versionErr != nil {
+		return versionErr
+	}
+	attribs := &encoded.Attributes
+	if attribs.attribs == nil {
+		attribs = newRollAttributes()
+	}
+	decoded, diceErr := NewDiceRollWithAttribs(encoded.Amount, encoded.Size, encoded.Modifier, attribs)
+	if diceErr != nil {
+		return diceErr
+	}
+	*diceRoll = *decoded
+	return nil
+}
+
+// Encodes the DiceRoll as JSON.
+func (diceRoll DiceRoll) MarshalJSON() ([]byte, error) {
+	return json.Marshal(diceRoll.encode())
+}
+
+// Decodes a JSON DiceRoll. Returns an error if invalid.
+func (diceRoll *DiceRoll) UnmarshalJSON(data []byte) error {
+	var encoded diceRollEncoding
+	if jsonErr := json.Unmarshal(data, &encoded); jsonErr != nil {
+		return jsonErr
+	}
+	return diceRoll.decode(encoded)
+}
+
+// Returns the DiceRoll value to encode as YAML.
+func (diceRoll DiceRoll) MarshalYAML() (any, error) {
+	return diceRoll.encode(), nil
+}
+
+// Decodes a YAML DiceRoll. Returns an error if invalid.
+func (diceRoll *DiceRoll) UnmarshalYAML(unmarshal func(any) error) error {
+	var encoded diceRollEncoding
+	if yamlErr := unmarshal(&encoded); yamlErr != nil {
+		return yamlErr
+	}
+	return diceRoll.decode(encoded)
+}
+
+// Returns the encoded rollAttributes.
+func (dndAttribs rollAttributes) encode() rollAttributesEncoding {
+	encoded := rollAttributesEncoding{}
+	encoded.Flags = DiceRoll{rollAttribs: &dndAttribs}.attributeStrs()
+	if dndAttribs.reroll != nil {
+		encoded.Reroll = dndAttribs.reroll.String()
+	}
+	if dndAttribs.explode != nil {
+		encoded.Explode = dndAttribs.explode.String()
+	}
+	for i := range dndAttribs.keepDrop {
+		encoded.KeepDrop = append(encoded.KeepDrop, dndAttribs.keepDrop[i].String())
+	}
+	if dndAttribs.success != nil {
+		encoded.Success = dndAttribs.success.String()
+	}
+	if dndAttribs.crits != nil {
+		encoded.Crits = dndAttribs.crits.String()
+	}
+	if dndAttribs.damageType != DamageUntyped {
+		encoded.DamageType = dndAttribs.damageType.String()
+	}
+	return encoded
+}
+
+// Decodes encoded rollAttributes. Returns an error for unknown flags, rules or damage types.
+func (dndAttribs *rollAttributes) decode(encoded rollAttributesEncoding) error {
+	// Rules are parsed as a dice token, keep and drop rules one at a time
+	keepDrop := ""
+	if len(encoded.KeepDrop) > 0 {
+		keepDrop = encoded.KeepDrop[0]
+	}
+	decoded, argErr := parseDiceRules(encoded.Reroll + encoded.Explode + keepDrop + encoded.Success + encoded.Crits)
+	if argErr != nil {
+		return argErr
+	}
+	for i := 1; i < len(encoded.KeepDrop); i++ {
+		rules, argErr := parseDiceRules(encoded.KeepDrop[i])
+		if argErr != nil {
+			return argErr
+		}
+		decoded.keepDrop = append(decoded.keepDrop, rules.keepDrop...)
+	}
+
+	for i := range encoded.Flags {
+		rollAttrib := lookupRollAttribute(encoded.Flags[i])
+		if rollAttrib == 0 {
+			return fmt.Errorf("unknown roll attribute %s", encoded.Flags[i])
+		}
+		decoded.setRollAttrib(rollAttrib)
+	}
+
+	if len(encoded.DamageType) > 0 {
+		decoded.damageType = checkForDamageType(encoded.DamageType)
+		if decoded.damageType == DamageUntyped {
+			return fmt.Errorf("unknown damage type %s", encoded.DamageType)
+		}
+	}
+
+	*dndAttribs = *decoded
+	return nil
+}
+
+// Parses dice token rules, such as "r1!dl1". Returns the rollAttributes holding the rules, an error if invalid.
+func parseDiceRules(rulesStr string) (*rollAttributes, error) {
+	diceStr := "d2" + rulesStr
+	matches := diceTokenRegex.FindStringSubmatch(diceStr)
+	if matches == nil || matches[0] != diceStr || matches[2] != "2" {
+		return nil, fmt.Errorf("invalid rules %s", rulesStr)
+	}
+	diceRoll, argErr := parseDiceToken(rollToken{diceToken, diceStr, 0, matches})
+	if argErr != nil {
+		return nil, argErr
+	}
+	return diceRoll.rollAttribs, nil
+}
+
+// Encodes the rollAttributes as JSON.
+func (dndAttribs rollAttributes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(dndAttribs.encode())
+}
+
+// Decodes JSON rollAttributes. Returns an error if invalid.
+func (dndAttribs *rollAttributes) UnmarshalJSON(data []byte) error {
+	var encoded rollAttributesEncoding
+	if jsonErr := json.Unmarshal(data, &encoded); jsonErr != nil {
+		return jsonErr
+	}
+	return dndAttribs.decode(encoded)
+}
+
+// Returns the rollAttributes value to encode as YAML.
+func (dndAttribs rollAttributes) MarshalYAML() (any, error) {
+	return dndAttribs.encode(), nil
+}
+
+// Decodes YAML rollAttributes. Returns an error if invalid.
+func (dndAttribs *rollAttributes) UnmarshalYAML(unmarshal func(any) error) error {
+	var encoded rollAttributesEncoding
+	if yamlErr := unmarshal(&encoded); yamlErr != nil {
+		return yamlErr
+	}
+	return dndAttribs.decode(encoded)
+}
+
+// Returns the encoded DiceRollResult.
+func (result DiceRollResult) encode() diceRollResultEncoding {
+	return diceRollResultEncoding{EncodingVersion, result.diceRoll, result.dice, result.sum, result.advDisDropped,
+		result.highDropped, result.lowDropped, result.explosions, result.rerolled, result.successes, result.failures,
+		result.critHit, result.critFail, result.critDice, result.stages}
+}
+
+// Decodes an encoded DiceRollResult. Returns an error if invalid.
+func (result *DiceRollResult) decode(encoded diceRollResultEncoding) error {
+	if versionErr := validateEncodingVersion(encoded.Version); versionErr != nil {
+		return versionErr
+	}
+	decoded := newDiceRollResult(encoded.DiceRoll)
+	decoded.dice = append(decoded.dice, encoded.Dice...)
+	decoded.sum = encoded.Sum
+	decoded.advDisDropped = append(decoded.advDisDropped, encoded.AdvDisDropped...)
+	decoded.highDropped = append(decoded.highDropped, encoded.HighDropped...)
+	decoded.lowDropped = append(decoded.lowDropped, encoded.LowDropped...)
+	decoded.explosions = append(decoded.explosions, encoded.Explosions...)
+	decoded.rerolled = append(decoded.rerolled, encoded.Rerolled...)
+	decoded.successes, decoded.failures = encoded.Successes, encoded.Failures
+	decoded.critHit, decoded.critFail = encoded.CritHit, encoded.CritFail
+	decoded.critDice = append(decoded.critDice, encoded.CritDice...)
+	decoded.stages = append(decoded.stages, encoded.Stages...)
+	*result = *decoded
+	return nil
+}
+
+// Encodes the DiceRollResult as JSON.
+func (result DiceRollResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(result.encode())
+}
+
+// Decodes a JSON DiceRollResult. Returns an error if invalid.
+func (result *DiceRollResult) UnmarshalJSON(data []byte) error {
+	var encoded diceRollResultEncoding
+	if jsonErr := json.Unmarshal(data, &encoded); jsonErr != nil {
+		return jsonErr
+	}
+	return result.decode(encoded)
+}
+
+// Returns the DiceRollResult value to encode as YAML.
+func (result DiceRollResult) MarshalYAML() (any, error) {
+	return result.encode(), nil
+}
+
+// Decodes a YAML DiceRollResult. Returns an error if invalid.
+func (result *DiceRollResult) UnmarshalYAML(unmarshal func(any) error) error {
+	var encoded diceRollResultEncoding
+	if yamlErr := unmarshal(&encoded); yamlErr != nil {
+		return yamlErr
+	}
+	return result.decode(encoded)
+}
+
+// Encodes the StageRecord as JSON.
+func (record StageRecord) MarshalJSON() ([]byte, error) {
+	return json.Marshal(record.encode())
+}
+
+// Decodes a JSON StageRecord.
+func (record *StageRecord) UnmarshalJSON(data []byte) error {
+	var encoded stageRecordEncoding
+	if jsonErr := json.Unmarshal(data, &encoded); jsonErr != nil {
+		return jsonErr
+	}
+	*record = StageRecord{encoded.Stage, encoded.SumBefore, encoded.SumAfter, encoded.DiceBefore, encoded.DiceAfter}
+	return nil
+}
+
+// Returns the StageRecord value to encode as YAML.
+func (record StageRecord) MarshalYAML() (any, error) {
+	return record.encode(), nil
+}
+
+// Decodes a YAML StageRecord.
+func (record *StageRecord) UnmarshalYAML(unmarshal func(any) error) error {
+	var encoded stageRecordEncoding
+	if yamlErr := unmarshal(&encoded); yamlErr != nil {
+		return yamlErr
+	}
+	*record = StageRecord{encoded.Stage, encoded.SumBefore, encoded.SumAfter, encoded.DiceBefore, encoded.DiceAfter}
+	return nil
+}
+
+// Encodes the StageRecord.
+func (record StageRecord) encode() stageRecordEncoding {
+	return stageRecordEncoding{record.stage, record.sumBefore, record.sumAfter, record.diceBefore, record.diceAfter}
+}
+
+// Encodes the ExplosionChain as JSON.
+func (chain ExplosionChain) MarshalJSON() ([]byte, error) {
+	return json.Marshal(explosionChainEncoding{chain.trigger, chain.extra})
+}
+
+// Decodes a JSON ExplosionChain.
+func (chain *ExplosionChain) UnmarshalJSON(data []byte) error {
+	var encoded explosionChainEncoding
+	if jsonErr := json.Unmarshal(data, &encoded); jsonErr != nil {
+		return jsonErr
+	}
+	*chain = ExplosionChain{encoded.Trigger, encoded.Extra}
+	return nil
+}
+
+// Returns the ExplosionChain value to encode as YAML.
+func (chain ExplosionChain) MarshalYAML() (any, error) {
+	return explosionChainEncoding{chain.trigger, chain.extra}, nil
+}
+
+// Decodes a YAML ExplosionChain.
+func (chain *ExplosionChain) UnmarshalYAML(unmarshal func(any) error) error {
+	var encoded explosionChainEncoding
+	if yamlErr := unmarshal(&encoded); yamlErr != nil {
+		return yamlErr
+	}
+	*chain = ExplosionChain{encoded.Trigger, encoded.Extra}
+	return nil
+}
+
+// Encodes the RerolledDie as JSON.
+func (die RerolledDie) MarshalJSON() ([]byte, error) {
+	return json.Marshal(rerolledDieEncoding{die.face, die.replacement})
+}
+
+// Decodes a JSON RerolledDie.
+func (die *RerolledDie) UnmarshalJSON(data []byte) error {
+	var encoded rerolledDieEncoding
+	if jsonErr := json.Unmarshal(data, &encoded); jsonErr != nil {
+		return jsonErr
+	}
+	*die = RerolledDie{encoded.Face, encoded.Replacement}
+	return nil
+}
+
+// Returns the RerolledDie value to encode as YAML.
+func (die RerolledDie) MarshalYAML() (any, error) {
+	return rerolledDieEncoding{die.face, die.replacement}, nil
+}
+
+// Decodes a YAML RerolledDie.
+func (die *RerolledDie) UnmarshalYAML(unmarshal func(any) error) error {
+	var encoded rerolledDieEncoding
+	if yamlErr := unmarshal(&encoded); yamlErr != nil {
+		return yamlErr
+	}
+	*die = RerolledDie{encoded.Face, encoded.Replacement}
+	return nil
+}
+
+// Returns the encoded FormulaResult.
+func (result FormulaResult) encode() formulaResultEncoding {
+	return formulaResultEncoding{result.formula.rollArg, result.results, result.sum}
+}
+
+// Decodes an encoded FormulaResult, parsing its formula again. Returns an error if invalid.
+func (result *FormulaResult) decode(encoded formulaResultEncoding) error {
+	_, formula, argErr := parseRollArgExpression(encoded.Formula)
+	if argErr != nil {
+		return argErr
+	}
+	if formula == nil {
+		return fmt.Errorf("invalid formula %s, reduces to DiceRolls", encoded.Formula)
+	}
+	if len(formula.diceRolls) != len(encoded.Results) {
+		return fmt.Errorf("invalid formula %s, has %d DiceRolls but %d results", encoded.Formula, len(formula.diceRolls), len(encoded.Results))
+	}
+
+	// The performed DiceRolls hold the rollAttributes of the formula
+	for i := range encoded.Results {
+		formula.diceRolls[i] = encoded.Results[i].diceRoll
+	}
+	*result = FormulaResult{*formula, encoded.Results, encoded.Sum}
+	return nil
+}
+
+// Encodes the FormulaResult as JSON.
+func (result FormulaResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(result.encode())
+}
+
+// Decodes a JSON FormulaResult. Returns an error if invalid.
+func (result *FormulaResult) UnmarshalJSON(data []byte) error {
+	var encoded formulaResultEncoding
+	if jsonErr := json.Unmarshal(data, &encoded); jsonErr != nil {
+		return jsonErr
+	}
+	return result.decode(encoded)
+}
+
+// Returns the FormulaResult value to encode as YAML.
+func (result FormulaResult) MarshalYAML() (any, error) {
+	return result.encode(), nil
+}
+
+// Decodes a YAML FormulaResult. Returns an error if invalid.
+func (result *FormulaResult) UnmarshalYAML(unmarshal func(any) error) error {
+	var encoded formulaResultEncoding
+	if yamlErr := unmarshal(&encoded); yamlErr != nil {
+		return yamlErr
+	}
+	return result.decode(encoded)
+}
+
+// Returns the encoded RollResult.
+func (rollResult RollResult) encode() rollResultEncoding {
+	return rollResultEncoding{EncodingVersion, rollResult.kind.String(), rollResult.results, rollResult.formulaResults, rollResult.seed}
+}
+
+// Decodes an encoded RollResult. Returns an error if invalid.
+func (rollResult *RollResult) decode(encoded rollResultEncoding) error {
+	if versionErr := validateEncodingVersion(encoded.Version); versionErr != nil {
+		return versionErr
+	}
+	decoded := newRollResult()
+	if decoded.kind = expressionKindMap[encoded.Kind]; decoded.kind == 0 {
+		return fmt.Errorf("unknown rolling expression kind %s", encoded.Kind)
+	}
+	decoded.results = append(decoded.results, encoded.Results...)
+	decoded.formulaResults = append(decoded.formulaResults, encoded.FormulaResults...)
+	decoded.seed = encoded.Seed
+	*rollResult = *decoded
+	return nil
+}
+
+// Encodes the RollResult as JSON.
+func (rollResult RollResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(rollResult.encode())
+}
+
+// Decodes a JSON RollResult. Returns an error if invalid.
+func (rollResult *RollResult) UnmarshalJSON(data []byte) error {
+	var encoded rollResultEncoding
+	if jsonErr := json.Unmarshal(data, &encoded); jsonErr != nil {
+		return jsonErr
+	}
+	return rollResult.decode(encoded)
+}
+
+// Returns the RollResult value to encode as YAML.
+func (rollResult RollResult) MarshalYAML() (any, error) {
+	return rollResult.encode(), nil
+}
+
+// Decodes a YAML RollResult. Returns an error if invalid.
+func (rollResult *RollResult) UnmarshalYAML(unmarshal func(any) error) error {
+	var encoded rollResultEncoding
+	if yamlErr := unmarshal(&encoded); yamlErr != nil {
+		return yamlErr
+	}
+	return rollResult.decode(encoded)
+}
@@ -0,0 +1,82 @@
+package diceroller
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestRollResultsJSONRoundTrip(t *testing.T) {
+	rollArgs := []string{"hit", "adv", "1d20cs>=19+5", "dmg", "2d6r1!+3", "fire", "(1d8+2)*2", "cold",
+		"roll", "droplow", "4d6dl1", "8d10>=7f1", "minus", "1d4"}
+	results, argErrs := NewSeededRoller(42).PerformRollArgs(rollArgs...)
+	if len(argErrs) > 0 {
+		t.Fatalf("Unexpected errors: %v", argErrs)
+	}
+
+	data, jsonErr := json.Marshal(results)
+	if jsonErr != nil {
+		t.Fatalf("Marshal error: %s", jsonErr)
+	}
+	var decoded []RollResult
+	if jsonErr := json.Unmarshal(data, &decoded); jsonErr != nil {
+		t.Fatalf("Unmarshal error: %s", jsonErr)
+	}
+	if !reflect.DeepEqual(results, decoded) {
+		t.Fatalf("Round trip changed results:\n%v\n%v", results, decoded)
+	}
+}
+
+func TestRollResultsYAMLRoundTrip(t *testing.T) {
+	rollArgs := []string{"hit", "adv", "1d20cs>=19+5", "dmg", "2d6r1!+3", "fire", "(1d8+2)*2", "cold",
+		"roll", "spell", "8d6!!>5", "8d10>=7f1", "minus", "1d4"}
+	results, argErrs := NewSeededRoller(42).PerformRollArgs(rollArgs...)
+	if len(argErrs) > 0 {
+		t.Fatalf("Unexpected errors: %v", argErrs)
+	}
+
+	data, yamlErr := yaml.Marshal(results)
+	if yamlErr != nil {
+		t.Fatalf("Marshal error: %s", yamlErr)
+	}
+	var decoded []RollResult
+	if yamlErr := yaml.Unmarshal(data, &decoded); yamlErr != nil {
+		t.Fatalf("Unmarshal error: %s", yamlErr)
+	}
+	if !reflect.DeepEqual(results, decoded) {
+		t.Fatalf("Round trip changed results:\n%v\n%v", results, decoded)
+	}
+
+	// Single DiceRolls too
+	diceRoll, _ := ParseDiceRoll("half 2d20r1kh1cs>=19+5")
+	data, _ = yaml.Marshal(diceRoll)
+	var decodedDiceRoll DiceRoll
+	if yamlErr := yaml.Unmarshal(data, &decodedDiceRoll); yamlErr != nil || !reflect.DeepEqual(*diceRoll, decodedDiceRoll) {
+		t.Fatalf("Round trip changed DiceRoll %s to %s with error %v", diceRoll, decodedDiceRoll, yamlErr)
+	}
+}
+
+func TestInvalidEncoding(t *testing.T) {
+	invalid := []string{
+		`{"version":2,"amount":1,"size":6,"modifier":0,"attributes":{}}`,
+		`{"version":1,"amount":0,"size":6,"modifier":0,"attributes":{}}`,
+		`{"version":1,"amount":1,"size":6,"modifier":0,"attributes":{"flags":["lucky"]}}`,
+		`{"version":1,"amount":1,"size":6,"modifier":0,"attributes":{"reroll":"6"}}`,
+		`{"version":1,"amount":1,"size":6,"modifier":0,"attributes":{"damageType":"sonic"}}`,
+		`{"version":1,"amount":4,"size":6,"modifier":0,"attributes":{"keepDrop":["dl1","kh2"]}}`,
+	}
+	for i := range invalid {
+		var diceRoll DiceRoll
+		if jsonErr := json.Unmarshal([]byte(invalid[i]), &diceRoll); jsonErr == nil {
+			t.Fatalf("Invalid DiceRoll %s returned no error", invalid[i])
+		}
+	}
+
+	var diceRoll DiceRoll
+	valid := `{"version":1,"amount":4,"size":6,"modifier":2,"attributes":{"flags":["adv"],"keepDrop":["dl1","dh1"]}}`
+	if jsonErr := json.Unmarshal([]byte(valid), &diceRoll); jsonErr != nil || diceRoll.String() != "adv drophigh 4d6dl1+2" {
+		t.Fatalf("Valid DiceRoll %s decoded to %s, %v", valid, diceRoll, jsonErr)
+	}
+}
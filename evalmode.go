@@ -0,0 +1,105 @@
+package diceroller
+
+import (
+	"fmt"
+)
+
+// An EvaluationMode selects how a Roller handles partially invalid input.
+type EvaluationMode int
+
+// EvaluationMode values. 0 is invalid.
+const (
+	LenientMode EvaluationMode = iota + 1 // Rolls valid input and reports invalid input, which is worth 0. The default
+	StrictMode  EvaluationMode = iota + 1 // Validates the whole input before rolling, returns no result if anything is invalid
+)
+
+var evaluationModeStrs = map[EvaluationMode]string{
+	LenientMode: "lenient",
+	StrictMode:  "strict",
+}
+
+// Human readable EvaluationMode string.
+func (mode EvaluationMode) String() string {
+	return evaluationModeStrs[mode]
+}
+
+// Sets how the Roller handles partially invalid input. Returns an error if invalid.
+func (roller *Roller) SetEvaluationMode(mode EvaluationMode) error {
+	if mode < LenientMode || mode > StrictMode {
+		return fmt.Errorf("invalid evaluation mode %d", mode)
+	}
+	roller.mode = mode
+	return nil
+}
+
+// Returns the default Roller evaluating in mode, for a single call such as WithEvaluationMode(StrictMode).PerformRollArgs(...).
+func WithEvaluationMode(mode EvaluationMode) *Roller {
+	return defaultRoller.WithEvaluationMode(mode)
+}
+
+// Returns a Roller evaluating in mode, for a single call such as roller.WithEvaluationMode(StrictMode).PerformRollArgs(...).
+// It shares the random source and settings of the Roller. Invalid modes evaluate in strict mode.
+func (roller *Roller) WithEvaluationMode(mode EvaluationMode) *Roller {
+	modeRoller := *roller
+	modeRoller.mode = mode
+	if mode != LenientMode {
+		modeRoller.mode = StrictMode
+	}
+	return &modeRoller
+}
+
+// Returns the current EvaluationMode of the Roller.
+func (roller *Roller) EvaluationMode() EvaluationMode {
+	return roller.mode
+}
+
+// Returns true if the Roller refuses partially invalid input.
+func (roller *Roller) strict() bool {
+	return roller.mode == StrictMode
+}
+
+// Validates every DiceRoll of rollExprs, formulas included, without rolling. Returns an error array for invalid DiceRolls.
+func validateRollingExpressions(rollExprs ...rollingExpression) (diceErrs []error) {
+	for e := range rollExprs {
+		for i := range rollExprs[e].diceRolls {
+			if diceErr := validateDiceRoll(rollExprs[e].diceRolls[i]); diceErr != nil {
+				diceErrs = append(diceErrs, diceErr)
+			}
+		}
+		for f := range rollExprs[e].formulas {
+			formula := rollExprs[e].formulas[f]
+			for i := range formula.diceRolls {
+				if diceErr := validateDiceRoll(formula.diceRolls[i]); diceErr != nil {
+					diceErrs = append(diceErrs, fmt.Errorf("%s: %w", formula.rollArg, diceErr))
+				}
+			}
+		}
+	}
+	return diceErrs
+}
+
+// Sums RollArgs, reporting invalid ones. Returns the sum and an error array for invalid RollArgs.
+// In strict mode, returns 0 if anything is invalid.
+func SumRollArgs(rollArgs ...string) (int, []error) {
+	return defaultRoller.SumRollArgs(rollArgs...)
+}
+
+// Sums DiceRolls, reporting invalid ones. Returns the sum and an error array for invalid DiceRolls.
+// In strict mode, returns 0 if anything is invalid.
+func SumDiceRolls(diceRolls ...DiceRoll) (int, []error) {
+	return defaultRoller.SumDiceRolls(diceRolls...)
+}
+
+// Sums RollArgs, reporting invalid ones. Returns the sum and an error array for invalid RollArgs.
+// In strict mode, returns 0 if anything is invalid.
+func (roller *Roller) SumRollArgs(rollArgs ...string) (int, []error) {
+	results, errs := roller.PerformRollArgs(rollArgs...)
+	return RollResultsSum(results...), errs
+}
+
+// Sums DiceRolls, reporting invalid ones. Returns the sum and an error array for invalid DiceRolls.
+// In strict mode, returns 0 if anything is invalid.
+func (roller *Roller) SumDiceRolls(diceRolls ...DiceRoll) (int, []error) {
+	results, errs := roller.PerformDiceRolls(diceRolls...)
+	return RollResultsSum(results...), errs
+}
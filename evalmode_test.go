@@ -0,0 +1,124 @@
+package diceroller
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStrictRollArgs(t *testing.T) {
+	roller := NewSeededRoller(42)
+	if modeErr := roller.SetEvaluationMode(StrictMode); modeErr != nil {
+		t.Fatalf("Valid evaluation mode returned an error: %s", modeErr.Error())
+	}
+
+	// Every error is reported and no die is rolled
+	results, errs := roller.PerformRollArgs("1d20+5", "advantge", "123456d6", "2d6")
+	if results != nil || len(errs) != 2 {
+		t.Fatalf("Strict mode returned %d RollResults and errors %v, expected none and 2 errors", len(results), errs)
+	}
+	if !errors.Is(errs[1], TooManyDice) {
+		t.Fatalf("Strict mode error %s doesn't match TooManyDice", errs[1].Error())
+	}
+	if roller.seeded.position != 0 {
+		t.Fatalf("Strict mode rolled dice out of invalid RollArgs, source at position %d", roller.seeded.position)
+	}
+
+	if sum, errs := roller.SumRollArgs("1d20+5", "1d1"); sum != 0 || len(errs) != 1 {
+		t.Fatalf("Strict sum of invalid RollArgs is %d with errors %v, expected 0 and 1 error", sum, errs)
+	}
+	if sum := roller.PerformRollArgsAndSum("1d20+5", "1d1"); sum != 0 {
+		t.Fatalf("Strict sum of invalid RollArgs is %d, expected 0", sum)
+	}
+
+	// Valid RollArgs roll as usual
+	if sum, errs := roller.SumRollArgs("1d20+5", "2d6"); sum < 8 || sum > 37 || len(errs) > 0 {
+		t.Fatalf("Strict sum of valid RollArgs is %d with errors %v", sum, errs)
+	}
+
+	// Errors only found while rolling discard the results
+	results, errs = NewRoller(maxSource{}).WithEvaluationMode(StrictMode).PerformRollArgs("1d20", "1d6/(1d6-6)")
+	if results != nil || len(errs) != 1 {
+		t.Fatalf("Strict division by zero returned %d RollResults and errors %v", len(results), errs)
+	}
+}
+
+func TestLenientRollArgs(t *testing.T) {
+	roller := NewRoller(maxSource{})
+	if roller.EvaluationMode() != LenientMode {
+		t.Fatalf("Default evaluation mode is %s, expected lenient", roller.EvaluationMode())
+	}
+
+	// Invalid RollArgs are worth 0
+	sum, errs := roller.SumRollArgs("1d20+5", "advantge", "123456d6", "2d6")
+	if sum != 37 || len(errs) != 2 {
+		t.Fatalf("Lenient sum is %d with errors %v, expected 37 and 2 errors", sum, errs)
+	}
+	if sum := roller.PerformDiceRollsAndSum(*newDiceRoll(1, 20, 5), DiceRoll{0, 6, 0, nil}); sum != 25 {
+		t.Fatalf("Lenient DiceRolls sum is %d, expected 25", sum)
+	}
+}
+
+func TestEvaluationModePerCall(t *testing.T) {
+	roller := NewRoller(maxSource{})
+
+	// Per call mode leaves the Roller mode untouched
+	if sum, errs := roller.WithEvaluationMode(StrictMode).SumDiceRolls(*newDiceRoll(1, 20, 5), DiceRoll{0, 6, 0, nil}); sum != 0 || len(errs) != 1 {
+		t.Fatalf("Strict per call sum is %d with errors %v, expected 0 and 1 error", sum, errs)
+	}
+	if roller.EvaluationMode() != LenientMode {
+		t.Fatalf("Per call evaluation mode changed the Roller mode to %s", roller.EvaluationMode())
+	}
+
+	roller.SetEvaluationMode(StrictMode)
+	if sum := roller.WithEvaluationMode(LenientMode).PerformRollArgsAndSum("1d20+5", "1d1"); sum != 25 {
+		t.Fatalf("Lenient per call sum is %d, expected 25", sum)
+	}
+
+	// Invalid modes
+	for _, mode := range []EvaluationMode{0, StrictMode + 1} {
+		if modeErr := roller.SetEvaluationMode(mode); modeErr == nil {
+			t.Fatalf("Invalid evaluation mode %d returned no error", mode)
+		}
+		if roller.WithEvaluationMode(mode).EvaluationMode() != StrictMode {
+			t.Fatalf("Invalid per call evaluation mode %d isn't strict", mode)
+		}
+	}
+}
+
+func TestStrictAttacksAndSaves(t *testing.T) {
+	roller := NewSeededRoller(7)
+	roller.SetEvaluationMode(StrictMode)
+
+	if resolutions, errs := roller.ResolveAttacks(15, "hit", "1d20+7", "dmg", "1d8+4", "1d1"); resolutions != nil || len(errs) != 1 {
+		t.Fatalf("Strict attacks returned %d AttackResolutions and errors %v", len(resolutions), errs)
+	}
+	if resolutions, errs := roller.ResolveAttacks(15, "dmg", "1d8+4"); resolutions != nil || len(errs) != 1 {
+		t.Fatalf("Strict attacks without attack roll returned %d AttackResolutions and errors %v", len(resolutions), errs)
+	}
+
+	targets := []SaveTarget{{"Goblin", 1, false}, {"Dragon", 123456, false}}
+	if results, errs := roller.ResolveSavingThrows(15, SaveHalf, targets, "8d6", "1d1"); results != nil || len(errs) != 2 {
+		t.Fatalf("Strict saving throws returned %d SaveResults and errors %v", len(results), errs)
+	}
+	if roller.seeded.position != 0 {
+		t.Fatalf("Strict mode rolled dice out of invalid input, source at position %d", roller.seeded.position)
+	}
+}
+
+func FuzzStrictRollArgs(f *testing.F) {
+	f.Add("1d20+5", "2d6")
+	f.Add("1d20+5", "advantge")
+	f.Fuzz(func(t *testing.T, rollArg1 string, rollArg2 string) {
+		lenientResults, lenientErrs := NewRoller(maxSource{}).PerformRollArgs(rollArg1, rollArg2)
+		strictResults, strictErrs := NewRoller(maxSource{}).WithEvaluationMode(StrictMode).PerformRollArgs(rollArg1, rollArg2)
+		if len(strictErrs) > 0 && strictResults != nil {
+			t.Fatalf("Strict mode returned RollResults with errors %v", strictErrs)
+		}
+		if len(lenientErrs) != len(strictErrs) {
+			t.Fatalf("Lenient errors %v differ from strict errors %v", lenientErrs, strictErrs)
+		}
+		if len(strictErrs) == 0 && RollResultsSum(strictResults...) != RollResultsSum(lenientResults...) {
+			t.Fatalf("Strict sum %d differs from lenient sum %d", RollResultsSum(strictResults...), RollResultsSum(lenientResults...))
+		}
+	})
+}
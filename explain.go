@@ -0,0 +1,180 @@
+package diceroller
+
+import (
+	"fmt"
+	"strings"
+)
+
+// An ExplanationStep is a transformation of a DiceRollResult sum, such as adding the modifier, with the sum before and after it.
+type ExplanationStep struct {
+	stage       string // Name of the RollStage applying the transformation
+	description string // Human readable transformation, such as "modifier +5"
+	before      int    // Sum before the transformation
+	after       int    // Sum after the transformation
+}
+
+// An Explanation lists the transformations computing a DiceRollResult sum, in order.
+type Explanation []ExplanationStep
+
+// Returns the name of the RollStage applying the transformation.
+func (step ExplanationStep) Stage() string {
+	return step.stage
+}
+
+// Returns the human readable transformation, such as "modifier +5".
+func (step ExplanationStep) Description() string {
+	return step.description
+}
+
+// Returns the sum before the transformation.
+func (step ExplanationStep) Before() int {
+	return step.before
+}
+
+// Returns the sum after the transformation.
+func (step ExplanationStep) After() int {
+	return step.after
+}
+
+// Human readable ExplanationStep string, such as "modifier +5: 12 -> 17".
+func (step ExplanationStep) String() string {
+	return fmt.Sprintf("%s: %d -> %d", step.description, step.before, step.after)
+}
+
+// Human readable Explanation string, one ExplanationStep per line.
+func (explanation Explanation) String() string {
+	stepStrs := make([]string, len(explanation))
+	for i := range explanation {
+		stepStrs[i] = "  " + explanation[i].String()
+	}
+	return strings.Join(stepStrs, "\n")
+}
+
+// Returns the Explanation of the sum: the rolled dice, then each RollStage changing the dice or the sum.
+func (result DiceRollResult) Explain() Explanation {
+	explanation := make(Explanation, 0)
+	for _, record := range result.stages {
+		if record.stage == GenerateStage || record.Changed() {
+			explanation = append(explanation, ExplanationStep{record.stage, result.describeStage(record), record.sumBefore, record.sumAfter})
+		}
+	}
+	return explanation
+}
+
+// Human readable Explanation of every DiceRollResult and FormulaResult, and of the sum.
+func (rollResult RollResult) ExplainText() string {
+	explainStrs := make([]string, 0)
+	for i := range rollResult.results {
+		explainStrs = append(explainStrs, rollResult.results[i].explainText(""))
+	}
+	for i := range rollResult.formulaResults {
+		explainStrs = append(explainStrs, rollResult.formulaResults[i].explainText())
+	}
+	explainStrs = append(explainStrs, fmt.Sprintf("Sum: %d", rollResult.Sum()))
+	return strings.Join(explainStrs, "\n")
+}
+
+// Human readable Explanation of the DiceRollResult, each line starting with indent.
+func (result DiceRollResult) explainText(indent string) string {
+	explainStr := fmt.Sprintf("%s%s:\n", indent, result.diceRoll)
+	for _, step := range result.Explain() {
+		explainStr += fmt.Sprintf("%s  %s\n", indent, step)
+	}
+	return explainStr + fmt.Sprintf("%s  = %d", indent, result.sum)
+}
+
+// Human readable Explanation of the FormulaResult DiceRolls, then of each operation with its intermediate value.
+func (result FormulaResult) explainText() string {
+	explainStr := fmt.Sprintf("%s:\n", result.formula.rollArg)
+	for i := range result.results {
+		explainStr += result.results[i].explainText("  ") + "\n"
+	}
+	for _, operation := range result.formula.root.explainOperations(result.results, nil) {
+		explainStr += fmt.Sprintf("  %s\n", operation)
+	}
+	return explainStr + fmt.Sprintf("  = %d", result.sum)
+}
+
+// Appends the human readable operations of the node to operations, operands first, such as "10 * 2 = 20".
+func (node *formulaNode) explainOperations(results []DiceRollResult, operations []string) []string {
+	if node.kind == numberToken || node.kind == diceToken {
+		return operations
+	}
+
+	// Evaluated formulas can't fail anymore
+	value, _ := node.evaluate(results)
+	right, _ := node.right.evaluate(results)
+	if node.left == nil {
+		return append(node.right.explainOperations(results, operations), fmt.Sprintf("negated %d = %d", right, value))
+	}
+	left, _ := node.left.evaluate(results)
+	operations = node.right.explainOperations(results, node.left.explainOperations(results, operations))
+
+	operationStr := fmt.Sprintf("%d %c %d = %d", left, node.kind.symbol(), right, value)
+	if node.kind == divideToken {
+		operationStr += ", rounded down"
+	}
+	return append(operations, operationStr)
+}
+
+// Returns the human readable transformation of a built-in RollStage, the name of custom ones.
+func (result DiceRollResult) describeStage(record StageRecord) string {
+	diceRoll := result.diceRoll
+	switch record.stage {
+	case GenerateStage:
+		return result.describeGenerate(record)
+	case KeepDropStage:
+		return result.describeKeepDrop()
+	case AfterDropsStage:
+		return "custom rollAttributes after drops"
+	case SuccessStage:
+		return fmt.Sprintf("counted %d successes and %d failures", result.successes, result.failures)
+	case ModifierStage:
+		return fmt.Sprintf("modifier %+d", diceRoll.modifier)
+	case AfterModifierStage:
+		return "custom rollAttributes after modifier"
+	case CritTotalStage:
+		return "critical total doubled"
+	case HalfStage:
+		return "halved, rounded down"
+	case MinimumStage:
+		return "raised to the minimum of 1"
+	case MinusStage:
+		return "negated by minus"
+	}
+	return record.stage
+}
+
+// Returns the human readable dropped dice, such as "dropped low [1]".
+func (result DiceRollResult) describeKeepDrop() string {
+	droppedStrs := make([]string, 0)
+	if len(result.highDropped) > 0 {
+		droppedStrs = append(droppedStrs, fmt.Sprintf("high %v", result.highDropped))
+	}
+	if len(result.lowDropped) > 0 {
+		droppedStrs = append(droppedStrs, fmt.Sprintf("low %v", result.lowDropped))
+	}
+	return "dropped " + strings.Join(droppedStrs, " and ")
+}
+
+// Returns the human readable rolled dice, such as "rolled 1d20 [17], advantage dropped [4]".
+func (result DiceRollResult) describeGenerate(record StageRecord) string {
+	generateStr := fmt.Sprintf("rolled %dd%d %v", result.diceRoll.diceAmmount, result.diceRoll.diceSize, record.diceAfter)
+	if len(result.critDice) > 0 {
+		generateStr += fmt.Sprintf(", crit dice %v", result.critDice)
+	}
+	if len(result.advDisDropped) > 0 {
+		advDisStr := "advantage"
+		if result.diceRoll.hasAttrib(DisadvantageAttrib) {
+			advDisStr = "disadvantage"
+		}
+		generateStr += fmt.Sprintf(", %s dropped %v", advDisStr, result.advDisDropped)
+	}
+	if len(result.rerolled) > 0 {
+		generateStr += fmt.Sprintf(", rerolled %v", result.rerolled)
+	}
+	if len(result.explosions) > 0 {
+		generateStr += fmt.Sprintf(", exploded %v", result.explosions)
+	}
+	return generateStr
+}
@@ -0,0 +1,133 @@
+package diceroller
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	roller := NewRoller(maxSource{})
+	roller.SetCritDamagePolicy(CritDoubleTotal, 0)
+
+	// Explanations with every die rolling its highest face
+	wantedExplanations := []struct {
+		rollArgs []string
+		steps    []string
+	}{
+		{[]string{"half", "4d6dl1-4"}, []string{
+			"rolled 4d6 [6 6 6 6]: 0 -> 24",
+			"dropped low [6]: 24 -> 18",
+			"modifier -4: 18 -> 14",
+			"halved, rounded down: 14 -> 7"}},
+		{[]string{"-1d4-8"}, []string{
+			"rolled 1d4 [4]: 0 -> 4",
+			"modifier -8: 4 -> -4",
+			"raised to the minimum of 1: -4 -> 1",
+			"negated by minus: 1 -> -1"}},
+		{[]string{"crit", "1d6+1"}, []string{
+			"rolled 1d6 [6]: 0 -> 6",
+			"modifier +1: 6 -> 7",
+			"critical total doubled: 7 -> 14"}},
+		{[]string{"dis", "1d20r<2+5"}, []string{
+			"rolled 1d20 [20], disadvantage dropped [20]: 0 -> 20",
+			"modifier +5: 20 -> 25"}},
+		{[]string{"5d10>7f<=2+1"}, []string{
+			"rolled 5d10 [10 10 10 10 10]: 0 -> 50",
+			"counted 5 successes and 0 failures: 50 -> 5",
+			"modifier +1: 5 -> 6"}},
+	}
+
+	for i := range wantedExplanations {
+		results, _ := roller.PerformRollArgs(wantedExplanations[i].rollArgs...)
+		explanation := results[0].results[0].Explain()
+		if len(explanation) != len(wantedExplanations[i].steps) {
+			t.Fatalf("%v explanation is\n%s\nexpected %d steps", wantedExplanations[i].rollArgs, explanation, len(wantedExplanations[i].steps))
+		}
+		for s := range explanation {
+			if explanation[s].String() != wantedExplanations[i].steps[s] {
+				t.Fatalf("%v explanation step %d is %q, expected %q", wantedExplanations[i].rollArgs, s, explanation[s], wantedExplanations[i].steps[s])
+			}
+		}
+
+		// The last step ends on the sum
+		if last := explanation[len(explanation)-1]; last.After() != results[0].results[0].Sum() {
+			t.Fatalf("%v explanation ends on %d, sum is %d", wantedExplanations[i].rollArgs, last.After(), results[0].results[0].Sum())
+		}
+	}
+}
+
+func TestExplainStructuredData(t *testing.T) {
+	results, _ := NewRoller(maxSource{}).PerformRollArgs("2d6kh1+3")
+	explanation := results[0].results[0].Explain()
+	wantedSteps := []struct {
+		stage       string
+		description string
+		before      int
+		after       int
+	}{
+		{GenerateStage, "rolled 2d6 [6 6]", 0, 12},
+		{KeepDropStage, "dropped low [6]", 12, 6},
+		{ModifierStage, "modifier +3", 6, 9},
+	}
+	for i := range wantedSteps {
+		step := explanation[i]
+		if step.Stage() != wantedSteps[i].stage || step.Description() != wantedSteps[i].description ||
+			step.Before() != wantedSteps[i].before || step.After() != wantedSteps[i].after {
+			t.Fatalf("Explanation step %d is %s, expected %v", i, step, wantedSteps[i])
+		}
+	}
+
+	// Explanations survive encoding
+	encoded, _ := json.Marshal(results[0].results[0])
+	var decoded DiceRollResult
+	if decodeErr := json.Unmarshal(encoded, &decoded); decodeErr != nil || !reflect.DeepEqual(decoded.Explain(), explanation) {
+		t.Fatalf("Decoded explanation is %v with error %v, expected %v", decoded.Explain(), decodeErr, explanation)
+	}
+}
+
+func TestExplainText(t *testing.T) {
+	roller := NewRoller(maxSource{})
+	results, _ := roller.PerformRollArgs("adv", "1d20+5", "(1d8+2)*2")
+	wantedText := "adv 1d20+5:\n" +
+		"  rolled 1d20 [20], advantage dropped [20]: 0 -> 20\n" +
+		"  modifier +5: 20 -> 25\n" +
+		"  = 25\n" +
+		"(1d8+2)*2:\n" +
+		"  adv 1d8:\n" +
+		"    rolled 1d8 [8], advantage dropped [8]: 0 -> 8\n" +
+		"    = 8\n" +
+		"  8 + 2 = 10\n" +
+		"  10 * 2 = 20\n" +
+		"  = 20\n" +
+		"Sum: 45"
+	if explainText := results[0].ExplainText(); explainText != wantedText {
+		t.Fatalf("Explanation text is\n%s\nexpected\n%s", explainText, wantedText)
+	}
+
+	// Nested, negated and divided formulas explain each operation
+	results, _ = NewRoller(maxSource{}).PerformRollArgs("-(1d6*3+1)/2")
+	wantedText = "-(1d6*3+1)/2:\n" +
+		"  1d6:\n" +
+		"    rolled 1d6 [6]: 0 -> 6\n" +
+		"    = 6\n" +
+		"  6 * 3 = 18\n" +
+		"  18 + 1 = 19\n" +
+		"  negated 19 = -19\n" +
+		"  -19 / 2 = -10, rounded down\n" +
+		"  = -10\n" +
+		"Sum: -10"
+	if explainText := results[0].ExplainText(); explainText != wantedText {
+		t.Fatalf("Explanation text is\n%s\nexpected\n%s", explainText, wantedText)
+	}
+
+	// Custom RollStages are explained by name
+	pipeline, _ := roller.RollPipeline().InsertAfter(ModifierStage, RollStage{"bless", func(state *RollState) {
+		state.SetSum(state.Sum() + state.RollDie(4))
+	}})
+	roller.SetRollPipeline(pipeline)
+	results, _ = roller.PerformRollArgs("1d20+5")
+	if explanation := results[0].results[0].Explain(); explanation[2].String() != "bless: 25 -> 29" {
+		t.Fatalf("Custom RollStage explanation is\n%s", explanation)
+	}
+}
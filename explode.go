@@ -0,0 +1,107 @@
+package diceroller
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type explodeMode int
+
+// explodeMode values. 0 is invalid.
+const (
+	explodeStandard  explodeMode = iota + 1 // Each exploding die adds an extra die
+	explodeCompound  explodeMode = iota + 1 // Extra dice are added to the exploding die
+	explodePenetrate explodeMode = iota + 1 // Each extra die is worth one less
+)
+
+// Allowed explodeMode strings in a RollArg.
+const (
+	explodeStr          string = "!"
+	explodeCompoundStr  string = "!!"
+	explodePenetrateStr string = "!p"
+)
+
+var explodeModeMap = map[string]explodeMode{
+	explodeStr:          explodeStandard,
+	explodeCompoundStr:  explodeCompound,
+	explodePenetrateStr: explodePenetrate,
+}
+
+// Max amount of extra dice a single exploding die can add, to avoid endless chains.
+const maxExplodeChainLength int = 100
+
+// An explodeRule describes when and how the dice of a DiceRoll explode.
+type explodeRule struct {
+	mode      explodeMode
+	threshold *rollThreshold // Dice matching the threshold explode, nil explodes on the highest face
+}
+
+// A chain of extra dice added by an exploding die.
+type ExplosionChain struct {
+	trigger int   // Die roll that started the explosion
+	extra   []int // Extra dice added, worth one less each when penetrating
+}
+
+// Returns the die roll that started the explosion.
+func (chain ExplosionChain) Trigger() int {
+	return chain.trigger
+}
+
+// Returns the extra dice added by the explosion.
+func (chain ExplosionChain) Extra() []int {
+	return slices.Clone(chain.extra)
+}
+
+// Parses an explode RollArg slice such as "!!" or "!p". Returns an explodeRule if valid, an error if invalid.
+func parseExplodeRule(modeStr string, thresholdStr string) (*explodeRule, error) {
+	mode := explodeModeMap[strings.ToLower(modeStr)]
+	if mode == 0 {
+		return nil, fmt.Errorf("invalid explode: %s", modeStr)
+	}
+
+	rule := &explodeRule{mode, nil}
+	if len(thresholdStr) > 0 {
+		threshold, argErr := parseRollThreshold(thresholdStr)
+		if argErr != nil {
+			return nil, argErr
+		}
+		rule.threshold = threshold
+	}
+
+	return rule, nil
+}
+
+// Returns true if roll explodes on a diceSize die.
+func (rule explodeRule) explodes(roll int, diceSize int) bool {
+	if rule.threshold == nil {
+		return roll == diceSize
+	}
+	return rule.threshold.matches(roll)
+}
+
+// Explode string, such as "!!>5".
+func (rule explodeRule) String() string {
+	explodeStr := rollAttributeMapKey(explodeModeMap, rule.mode)
+	if rule.threshold != nil {
+		explodeStr += rule.threshold.String()
+	}
+	return explodeStr
+}
+
+// Validates an explodeRule for a diceSize die. Returns nil if valid, an error if invalid.
+func validateExplodeRule(rule *explodeRule, diceSize int) error {
+	if rule != nil && rule.threshold != nil && rule.threshold.matchingFaces(diceSize) == diceSize {
+		return fmt.Errorf("invalid explode %s, explodes on every face", rule)
+	}
+	return nil
+}
+
+// Human readable ExplosionChain string, such as "6!6!3".
+func (chain ExplosionChain) String() string {
+	chainStr := fmt.Sprint(chain.trigger)
+	for i := range chain.extra {
+		chainStr += fmt.Sprintf("!%d", chain.extra[i])
+	}
+	return chainStr
+}
@@ -0,0 +1,152 @@
+package diceroller
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// Valid exploding Roll Args
+var validExplodeRollArgs = []string{
+	"3d6!",
+	"4d10!!",
+	"2d6!p",
+	"2d6!P+1",
+	"1d6!>4+2",
+	"5d8!<=2-1",
+	"d20!!=20",
+	"-2d4!3"}
+
+// Invalid exploding Roll Args
+var invalidExplodeRollArgs = []string{
+	"1d6!>0",
+	"1d6!<7",
+	"1d6!!!",
+	"1d6!x",
+	"1d6!>",
+	"1d6+1!"}
+
+func TestParseValidExplodeRollArgs(t *testing.T) {
+	for i := range validExplodeRollArgs {
+		diceRoll, argErr := parseRollArg(validExplodeRollArgs[i])
+		if argErr != nil {
+			validArgParsingError(argErr, t)
+		}
+		if diceRoll.explodeRule() == nil {
+			t.Fatalf("RollArg %s parsed without an explodeRule", validExplodeRollArgs[i])
+		}
+	}
+}
+
+func TestParseInvalidExplodeRollArgs(t *testing.T) {
+	for i := range invalidExplodeRollArgs {
+		if _, argErr := parseRollArg(invalidExplodeRollArgs[i]); argErr == nil {
+			invalidArgParsingError(invalidExplodeRollArgs[i], t)
+		}
+	}
+}
+
+func TestExplodeChainCap(t *testing.T) {
+	// Max source rolls the highest face every time, every die explodes until the cap
+	wantedSums := map[string]int{
+		"1d6!":   6 + maxExplodeChainLength*6,
+		"1d6!!":  6 + maxExplodeChainLength*6,
+		"1d6!p":  6 + maxExplodeChainLength*5,
+		"2d6!>5": 2 * (6 + maxExplodeChainLength*6),
+	}
+
+	for rollArg, wantedSum := range wantedSums {
+		results, _ := NewRoller(maxSource{}).PerformRollArgs(rollArg)
+		result := results[0].results[0]
+
+		if result.sum != wantedSum {
+			t.Fatalf("%s with max source result = %d, wanted %d", rollArg, result.sum, wantedSum)
+		}
+
+		for i := range result.explosions {
+			if chainLength := len(result.explosions[i].extra); chainLength != maxExplodeChainLength {
+				t.Fatalf("%s explosion chain length = %d, wanted %d", rollArg, chainLength, maxExplodeChainLength)
+			}
+		}
+	}
+}
+
+func TestExplodeKeepsExtraDiceApart(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		results, _ := PerformRollArgs("10d4!", "10d4!!")
+
+		exploding := results[0].results[0]
+		if len(exploding.dice) != 10 {
+			t.Fatalf("10d4! kept %d dice, wanted 10", len(exploding.dice))
+		}
+
+		// Sum is the rolled dice plus every extra die
+		sum := 0
+		for d := range exploding.dice {
+			sum += exploding.dice[d]
+		}
+		for c := range exploding.explosions {
+			if exploding.explosions[c].trigger != 4 {
+				t.Fatalf("10d4! exploded on %d, wanted 4", exploding.explosions[c].trigger)
+			}
+			for e := range exploding.explosions[c].extra {
+				sum += exploding.explosions[c].extra[e]
+			}
+		}
+		if sum != exploding.sum {
+			t.Fatalf("10d4! result = %d, wanted %d", exploding.sum, sum)
+		}
+
+		// Compounded dice hold their whole chain
+		compounding := results[0].results[1]
+		for c := range compounding.explosions {
+			chain := compounding.explosions[c]
+			total := chain.trigger
+			for e := range chain.extra {
+				total += chain.extra[e]
+			}
+			if !slices.Contains(compounding.dice, total) {
+				t.Fatalf("10d4!! rolled %v, wanted a die worth %d for chain %s", compounding.dice, total, chain)
+			}
+		}
+	}
+}
+
+func TestExplodeDroppedDiceDropTheirChain(t *testing.T) {
+	// Max source explodes both dice until the cap, only the kept chain counts
+	if sum := NewRoller(maxSource{}).PerformRollArgsAndSum("2d6!kl1"); sum != 6+maxExplodeChainLength*6 {
+		t.Fatalf("2d6!kl1 with max source result = %d, wanted %d", sum, 6+maxExplodeChainLength*6)
+	}
+
+	for i := 0; i < 100; i++ {
+		results, _ := PerformRollArgs("4d4!kl1", "4d4!pdh3")
+		for r := range results[0].results {
+			// A kept die below the highest face didn't explode, dropped chains add nothing
+			result := results[0].results[r]
+			if result.dice[0] < 4 && result.sum != result.dice[0] {
+				t.Fatalf("%s kept %v and dropped %v, result = %d, wanted %d", result.diceRoll, result.dice,
+					append(result.highDropped, result.lowDropped...), result.sum, result.dice[0])
+			}
+		}
+	}
+}
+
+func TestExplodeRuleString(t *testing.T) {
+	for i := range validExplodeRollArgs {
+		diceRoll, _ := parseRollArg(validExplodeRollArgs[i])
+		reparsed, argErr := parseRollArg(diceRoll.String())
+		if argErr != nil {
+			t.Fatalf("DiceRoll %s string is not a valid RollArg: %s", diceRoll, argErr.Error())
+		}
+		if reparsed.String() != diceRoll.String() {
+			t.Fatalf("DiceRoll = %s, wanted %s", reparsed, diceRoll)
+		}
+	}
+}
+
+func FuzzExplodeRollArg(f *testing.F) {
+	f.Add(3, 6, ">=5")
+	f.Fuzz(func(t *testing.T, diceAmmount int, diceSize int, threshold string) {
+		PerformRollArgs(fmt.Sprintf("%dd%d!%s", diceAmmount, diceSize, threshold))
+	})
+}
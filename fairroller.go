@@ -0,0 +1,100 @@
+package diceroller
+
+import (
+	"crypto/hmac"
+	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+)
+
+// Size in bytes of generated server seeds.
+const serverSeedSize int = 32
+
+// A FairProof holds everything needed to Verify provably fair rolls once the server seed is revealed.
+type FairProof struct {
+	Commitment string // Hex SHA-256 hash of ServerSeed, published before the client seed is known
+	ServerSeed string // Server seed, revealed after rolling
+	ClientSeed string // Seed supplied by the player
+	Nonce      uint64 // Nonce of the rolls, incremented for each new roll with the same seeds
+}
+
+// Generates a random hex encoded server seed using crypto/rand.
+func NewServerSeed() string {
+	seed := make([]byte, serverSeedSize)
+	crand.Read(seed)
+	return hex.EncodeToString(seed)
+}
+
+// Returns the commitment to publish for serverSeed before rolling, its hex SHA-256 hash.
+func CommitServerSeed(serverSeed string) string {
+	hash := sha256.Sum256([]byte(serverSeed))
+	return hex.EncodeToString(hash[:])
+}
+
+// Provably fair Roller constructor. Every die is drawn from HMAC-SHA256 blocks keyed
+// with serverSeed over the message "clientSeed:nonce:round", round starting at 0.
+// Each block provides four little endian uint64 values to the Roller.
+func NewFairRoller(serverSeed string, clientSeed string, nonce uint64) *Roller {
+	return NewRoller(newFairSource(serverSeed, clientSeed, nonce))
+}
+
+// Recomputes the results of RollArgs rolled by a fair Roller with default settings from the revealed seeds of proof.
+// Returns an error if the server seed doesn't match the commitment or if a RollArg is invalid.
+// Results can then be compared to the claimed ones, dropped dice and sums included.
+func Verify(proof FairProof, rollArgs ...string) ([]RollResult, error) {
+	return defaultRoller.Verify(proof, rollArgs...)
+}
+
+// Recomputes the results of RollArgs from the revealed seeds of proof, with the critical range, critical damage
+// policy, evaluation mode and RollPipeline of the Roller. The Roller source is left untouched.
+// Returns an error if the server seed doesn't match the commitment or if a RollArg is invalid.
+func (roller *Roller) Verify(proof FairProof, rollArgs ...string) ([]RollResult, error) {
+	if !hmac.Equal([]byte(CommitServerSeed(proof.ServerSeed)), []byte(proof.Commitment)) {
+		return nil, fmt.Errorf("server seed does not match commitment %s", proof.Commitment)
+	}
+
+	verifyRoller := *roller
+	verifyRoller.rand, verifyRoller.seeded = rand.New(newFairSource(proof.ServerSeed, proof.ClientSeed, proof.Nonce)), nil
+	results, errs := verifyRoller.PerformRollArgs(rollArgs...)
+	if errs != nil {
+		return nil, errors.Join(errs...)
+	}
+
+	return results, nil
+}
+
+// Random source deriving values from HMAC-SHA256 of the server and client seeds.
+type fairSource struct {
+	key        []byte // HMAC key, the server seed
+	clientSeed string
+	nonce      uint64
+	round      uint64   // Index of the next HMAC block
+	block      [32]byte // Current HMAC block
+	cursor     int      // Bytes consumed in the current block
+}
+
+// Constructor of fairSource.
+func newFairSource(serverSeed string, clientSeed string, nonce uint64) *fairSource {
+	source := &fairSource{key: []byte(serverSeed), clientSeed: clientSeed, nonce: nonce}
+	source.cursor = len(source.block) // No block computed yet
+	return source
+}
+
+func (source *fairSource) Uint64() uint64 {
+	// Current block is used up, compute the next one
+	if source.cursor >= len(source.block) {
+		mac := hmac.New(sha256.New, source.key)
+		fmt.Fprintf(mac, "%s:%d:%d", source.clientSeed, source.nonce, source.round)
+		copy(source.block[:], mac.Sum(nil))
+		source.round++
+		source.cursor = 0
+	}
+
+	value := binary.LittleEndian.Uint64(source.block[source.cursor:])
+	source.cursor += 8
+	return value
+}
@@ -0,0 +1,80 @@
+package diceroller
+
+import (
+	"testing"
+)
+
+func TestFairRollerVerify(t *testing.T) {
+	rollArgs := []string{"hit", "adv", "1d20+5", "dmg", "drophigh", "droplow", "4d6+2"}
+	serverSeed := NewServerSeed()
+	proof := FairProof{CommitServerSeed(serverSeed), serverSeed, "player chosen seed", 7}
+
+	results, _ := NewFairRoller(proof.ServerSeed, proof.ClientSeed, proof.Nonce).PerformRollArgs(rollArgs...)
+
+	verified, err := Verify(proof, rollArgs...)
+	if err != nil {
+		t.Fatalf("Verify returned error: %s", err.Error())
+	}
+
+	for e := range results {
+		for i := range results[e].results {
+			if !sameDiceRollResult(results[e].results[i], verified[e].results[i]) {
+				t.Fatalf("Verified %s, wanted %s", verified[e].results[i], results[e].results[i])
+			}
+		}
+	}
+}
+
+func TestFairRollerVerifyWithSettings(t *testing.T) {
+	rollArgs := []string{"hit", "1d20+5", "dmg", "crit", "2d6+3"}
+	serverSeed := NewServerSeed()
+	proof := FairProof{CommitServerSeed(serverSeed), serverSeed, "player chosen seed", 3}
+
+	roller := NewFairRoller(proof.ServerSeed, proof.ClientSeed, proof.Nonce)
+	roller.SetCritDamagePolicy(CritMaxPlusRoll, 1)
+	roller.SetCritRange(15, 2)
+	results, _ := roller.PerformRollArgs(rollArgs...)
+
+	verified, err := roller.Verify(proof, rollArgs...)
+	if err != nil {
+		t.Fatalf("Verify returned error: %s", err.Error())
+	}
+	for e := range results {
+		for i := range results[e].results {
+			if !sameDiceRollResult(results[e].results[i], verified[e].results[i]) {
+				t.Fatalf("Verified %s, wanted %s", verified[e].results[i], results[e].results[i])
+			}
+		}
+	}
+}
+
+func TestFairRollerNonceChangesRolls(t *testing.T) {
+	serverSeed := NewServerSeed()
+	diceRoll := *newDiceRoll(20, 100, 0)
+
+	if sum1, sum2 := NewFairRoller(serverSeed, "client", 1).Roll(diceRoll), NewFairRoller(serverSeed, "client", 2).Roll(diceRoll); sum1 == sum2 {
+		t.Fatalf("Fair Rollers with different nonces both rolled %d", sum1)
+	}
+}
+
+func TestVerifyWithWrongServerSeed(t *testing.T) {
+	proof := FairProof{CommitServerSeed(NewServerSeed()), NewServerSeed(), "client", 0}
+	if _, err := Verify(proof, "1d20"); err == nil {
+		t.Fatalf("Verify with a server seed not matching the commitment did not return an error")
+	}
+}
+
+func TestVerifyWithInvalidRollArgs(t *testing.T) {
+	serverSeed := NewServerSeed()
+	proof := FairProof{CommitServerSeed(serverSeed), serverSeed, "client", 0}
+	if _, err := Verify(proof, invalidRollArgs...); err == nil {
+		t.Fatalf("Verify with invalid RollArgs did not return an error")
+	}
+}
+
+func FuzzFairRoller(f *testing.F) {
+	f.Add("server", "client", uint64(0), "2d20+1")
+	f.Fuzz(func(t *testing.T, serverSeed string, clientSeed string, nonce uint64, fuzzedRollArg string) {
+		NewFairRoller(serverSeed, clientSeed, nonce).PerformRollArgs(fuzzedRollArg)
+	})
+}
@@ -2,4 +2,7 @@ module github.com/skyestalimit/diceroller
 
 go 1.23.3
 
-require golang.org/x/exp v0.0.0-20241108190413-2d47ceb2692f
+require (
+	golang.org/x/exp v0.0.0-20241108190413-2d47ceb2692f
+	gopkg.in/yaml.v3 v3.0.1
+)
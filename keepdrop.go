@@ -0,0 +1,188 @@
+package diceroller
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type keepDropOp int
+
+// keepDropOp values. 0 is invalid.
+const (
+	keepHighOp keepDropOp = iota + 1
+	keepLowOp  keepDropOp = iota + 1
+	dropHighOp keepDropOp = iota + 1
+	dropLowOp  keepDropOp = iota + 1
+)
+
+// Allowed keepDropOp strings in a RollArg.
+const (
+	keepHighStr string = "kh"
+	keepLowStr  string = "kl"
+	dropHighStr string = "dh"
+	dropLowStr  string = "dl"
+)
+
+var keepDropOpMap = map[string]keepDropOp{
+	keepHighStr: keepHighOp,
+	keepLowStr:  keepLowOp,
+	dropHighStr: dropHighOp,
+	dropLowStr:  dropLowOp,
+}
+
+// Allowed keepDropRule alias strings as RollArg, replacing the single die drop rollAttributes.
+const (
+	dropHighAliasStr string = "drophigh"
+	dropLowAliasStr  string = "droplow"
+)
+
+var keepDropAliasMap = map[string]keepDropRule{
+	dropHighAliasStr: {dropHighOp, 1},
+	dropLowAliasStr:  {dropLowOp, 1},
+}
+
+// A keepDropRule keeps or drops the count highest or lowest dice of a DiceRoll.
+type keepDropRule struct {
+	op    keepDropOp
+	count int
+}
+
+// Parses a keep or drop RollArg slice such as "kh3". Returns a keepDropRule if valid, an error if invalid.
+func parseKeepDropRule(opStr string, countStr string) (*keepDropRule, error) {
+	op := keepDropOpMap[strings.ToLower(opStr)]
+	if op == 0 {
+		return nil, fmt.Errorf("invalid keep or drop: %s", opStr)
+	}
+
+	count, argErr := parseRollArgSlice(countStr)
+	if argErr != nil {
+		return nil, argErr
+	}
+
+	return &keepDropRule{op, count}, nil
+}
+
+// Checks if the rollArg is a keepDropRule alias. Returns the keepDropRule if it matches, otherwise nil.
+func checkForKeepDropAlias(rollArg string) *keepDropRule {
+	if rule, found := keepDropAliasMap[rollArg]; found {
+		return &rule
+	}
+	return nil
+}
+
+// Returns the ammount of high and low dice to drop out of diceAmmount dice. At least one die is always kept.
+func (rule keepDropRule) dropCounts(diceAmmount int) (high int, low int) {
+	switch rule.op {
+	case keepHighOp:
+		low = diceAmmount - rule.count
+	case keepLowOp:
+		high = diceAmmount - rule.count
+	case dropHighOp:
+		high = rule.count
+	case dropLowOp:
+		low = rule.count
+	}
+	return max(min(high, diceAmmount-1), 0), max(min(low, diceAmmount-1), 0)
+}
+
+// Keep or drop string, such as "kh3".
+func (rule keepDropRule) String() string {
+	return fmt.Sprintf("%s%d", rollAttributeMapKey(keepDropOpMap, rule.op), rule.count)
+}
+
+// Keep or drop alias string, such as "droplow". Rules without alias return their keep or drop string.
+func (rule keepDropRule) aliasString() string {
+	if aliasStr := rollAttributeMapKey(keepDropAliasMap, rule); len(aliasStr) > 0 {
+		return aliasStr
+	}
+	return rule.String()
+}
+
+// Validates keepDropRules of diceAmmount dice. Rules following the first one must have an alias, and at least one
+// die must be kept. As their rollAttribute ancestors, drophigh and droplow do nothing to a single die. Returns nil
+// if valid, an error if invalid.
+func validateKeepDropRules(rules []keepDropRule, diceAmmount int) error {
+	remaining := diceAmmount
+	for i := range rules {
+		if rules[i].count <= 0 {
+			return fmt.Errorf("invalid keep or drop %s", rules[i])
+		}
+		if i > 0 && checkForKeepDropAlias(rules[i].aliasString()) == nil {
+			return fmt.Errorf("invalid keep or drop %s, only %s and %s can follow another", rules[i], dropHighAliasStr, dropLowAliasStr)
+		}
+
+		// Keeping more dice than remaining, or dropping all of them
+		kept := rules[i].count
+		if rules[i].op == dropHighOp || rules[i].op == dropLowOp {
+			kept = remaining - rules[i].count
+		}
+		if remaining == 1 && checkForKeepDropAlias(rules[i].aliasString()) != nil {
+			kept = remaining
+		}
+		if kept < 1 || kept > remaining {
+			return fmt.Errorf("invalid keep or drop %s, %d dice remaining", rules[i].aliasString(), remaining)
+		}
+		remaining = kept
+	}
+	return nil
+}
+
+// Applies drop high logic, dropping the count highest dice. Ties drop the first rolled die.
+func dropHigh(state *RollState, count int) {
+	dropIndexes := sortedDiceIndexes(state.result.dice, func(roll int, roll2 int) int { return roll2 - roll })
+	state.result.highDropped = append(state.result.highDropped, dropDice(state, dropIndexes[:count])...)
+}
+
+// Applies drop low logic, dropping the count lowest dice. Ties drop the first rolled die.
+func dropLow(state *RollState, count int) {
+	dropIndexes := sortedDiceIndexes(state.result.dice, func(roll int, roll2 int) int { return roll - roll2 })
+	state.result.lowDropped = append(state.result.lowDropped, dropDice(state, dropIndexes[:count])...)
+}
+
+// Returns the indexes of dice sorted using cmp, ties keep the rolled order.
+func sortedDiceIndexes(dice []int, cmp func(roll int, roll2 int) int) []int {
+	indexes := make([]int, len(dice))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	slices.SortStableFunc(indexes, func(i int, j int) int { return cmp(dice[i], dice[j]) })
+	return indexes
+}
+
+// Removes dice at dropIndexes from the dice and the sum, along with their roll details and explosion chains.
+// Returns the dropped dice in rolled order.
+func dropDice(state *RollState, dropIndexes []int) (dropped []int) {
+	if len(dropIndexes) == 0 {
+		return dropped
+	}
+
+	diceRollResult := state.result
+	toDrop := make([]bool, len(diceRollResult.dice))
+	for i := range dropIndexes {
+		toDrop[dropIndexes[i]] = true
+	}
+
+	keptDice := make([]int, 0, len(diceRollResult.dice))
+	keptRolled := make([]rolledDie, 0, len(state.rolled))
+	keptChains := make([]ExplosionChain, 0, len(diceRollResult.explosions))
+	for i := range diceRollResult.dice {
+		rolled := rolledDie{diceRollResult.dice[i], 0, -1}
+		if i < len(state.rolled) {
+			rolled = state.rolled[i]
+		}
+		if toDrop[i] {
+			dropped = append(dropped, diceRollResult.dice[i])
+			diceRollResult.sum -= diceRollResult.dice[i] + rolled.extraSum
+			continue
+		}
+		if rolled.chain >= 0 {
+			keptChains = append(keptChains, diceRollResult.explosions[rolled.chain])
+			rolled.chain = len(keptChains) - 1
+		}
+		keptDice = append(keptDice, diceRollResult.dice[i])
+		keptRolled = append(keptRolled, rolled)
+	}
+	diceRollResult.dice, diceRollResult.explosions, state.rolled = keptDice, keptChains, keptRolled
+	return dropped
+}
@@ -0,0 +1,120 @@
+package diceroller
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// Valid keep and drop Roll Args
+var validKeepDropRollArgs = []string{
+	"4d6kh3",
+	"2d20kl1",
+	"8d6dl2",
+	"3d10DH1+4",
+	"4d6r1!kh3-1",
+	"d20kh1"}
+
+// Invalid keep and drop Roll Args
+var invalidKeepDropRollArgs = []string{
+	"4d6kh0",
+	"4d6k3",
+	"4d6kh",
+	"4d6hk3",
+	"4d6+1kh3",
+	"4d6kh3dl1",
+	"8d6dh9",
+	"3d6kh5",
+	"4d6dh4"}
+
+func TestParseValidKeepDropRollArgs(t *testing.T) {
+	for i := range validKeepDropRollArgs {
+		diceRoll, argErr := parseRollArg(validKeepDropRollArgs[i])
+		if argErr != nil {
+			validArgParsingError(argErr, t)
+		}
+		if len(diceRoll.keepDropRules()) != 1 {
+			t.Fatalf("RollArg %s parsed without a keepDropRule", validKeepDropRollArgs[i])
+		}
+	}
+}
+
+func TestParseInvalidKeepDropRollArgs(t *testing.T) {
+	for i := range invalidKeepDropRollArgs {
+		if _, argErr := parseRollArg(invalidKeepDropRollArgs[i]); argErr == nil {
+			invalidArgParsingError(invalidKeepDropRollArgs[i], t)
+		}
+	}
+}
+
+func TestKeepDropCounts(t *testing.T) {
+	wantedKept := map[string]int{
+		"4d6kh3":  3,
+		"2d20kl1": 1,
+		"8d6dl2":  6,
+		"8d6dh7":  1,
+		"1d6dl1":  1,
+		"3d6kh3":  3,
+	}
+
+	for rollArg, kept := range wantedKept {
+		results, _ := PerformRollArgs(rollArg)
+		result := results[0].results[0]
+		dropped := len(result.highDropped) + len(result.lowDropped)
+
+		if len(result.dice) != kept {
+			t.Fatalf("%s kept %d dice, wanted %d", rollArg, len(result.dice), kept)
+		}
+		if len(result.dice)+dropped != result.diceRoll.diceAmmount {
+			t.Fatalf("%s kept %d and dropped %d dice, wanted %d total", rollArg, len(result.dice), dropped, result.diceRoll.diceAmmount)
+		}
+	}
+}
+
+func TestKeepDropKeepsHighestAndLowest(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		results, _ := PerformRollArgs("8d6kh3", "roll", "8d6kl3")
+		keepHigh, keepLow := results[0].results[0], results[1].results[0]
+
+		if slices.Min(keepHigh.dice) < slices.Max(keepHigh.lowDropped) {
+			t.Fatalf("8d6kh3 kept %v and dropped %v", keepHigh.dice, keepHigh.lowDropped)
+		}
+		if slices.Max(keepLow.dice) > slices.Min(keepLow.highDropped) {
+			t.Fatalf("8d6kl3 kept %v and dropped %v", keepLow.dice, keepLow.highDropped)
+		}
+	}
+}
+
+func TestDropDiceKeepsRolledOrder(t *testing.T) {
+	result := newDiceRollResult(*newDiceRoll(6, 6, 0))
+	result.dice = []int{5, 1, 6, 2, 6, 3}
+	result.sum = 23
+
+	state := &RollState{nil, result, nil, nil}
+	dropHigh(state, 2)
+	dropLow(state, 2)
+
+	if fmt.Sprint(result.highDropped, result.lowDropped, result.dice, result.sum) != "[6 6] [1 2] [5 3] 8" {
+		t.Fatalf("Dropped high %v and low %v, kept %v summing %d", result.highDropped, result.lowDropped, result.dice, result.sum)
+	}
+}
+
+func TestKeepDropAliases(t *testing.T) {
+	results, _ := PerformRollArgs("drophigh", "droplow", "4d6")
+	result := results[0].results[0]
+
+	if len(result.dice) != 2 || len(result.highDropped) != 1 || len(result.lowDropped) != 1 {
+		t.Fatalf("drophigh droplow 4d6 result = %s", result)
+	}
+
+	if diceRollStr := result.diceRoll.String(); diceRollStr != "droplow 4d6dh1" {
+		t.Fatalf("drophigh droplow 4d6 DiceRoll = %s, wanted droplow 4d6dh1", diceRollStr)
+	}
+}
+
+func FuzzKeepDropRollArg(f *testing.F) {
+	f.Add(4, 6, "kh", 3)
+	f.Fuzz(func(t *testing.T, diceAmmount int, diceSize int, op string, count int) {
+		PerformRollArgs(fmt.Sprintf("%dd%d%s%d", diceAmmount, diceSize, op, count))
+	})
+}
@@ -0,0 +1,322 @@
+package diceroller
+
+import (
+	"fmt"
+	"slices"
+)
+
+// A RollStage is a named step of a RollPipeline, changing the dice and sum of the RollState it's applied to.
+type RollStage struct {
+	Name  string           // Unique name of the stage in its RollPipeline
+	Apply func(*RollState) // Changes the RollState
+}
+
+// A RollPipeline is the ordered list of RollStages performing each DiceRoll. Each RollStage records its
+// effect in the DiceRollResult, see DiceRollResult.Stages.
+type RollPipeline []RollStage
+
+// Names of the RollStages of the default RollPipeline, in order.
+const (
+	GenerateStage      string = "generate"      // Rolls the dice, applying rerolls, advantage, explosions and PerDie hooks
+	KeepDropStage      string = "keepdrop"      // Applies keep and drop rules
+	AfterDropsStage    string = "afterdrops"    // Applies AfterDrops hooks of custom rollAttributes
+	SuccessStage       string = "success"       // Counts successes of dice pools, the sum becomes the net successes
+	ModifierStage      string = "modifier"      // Adds the modifier
+	AfterModifierStage string = "aftermodifier" // Applies AfterModifier hooks of custom rollAttributes
+	CritTotalStage     string = "crittotal"     // Doubles the sum of critical DiceRolls with the CritDoubleTotal policy
+	HalfStage          string = "half"          // Halves the sum, rounding down
+	MinimumStage       string = "minimum"       // Raises the sum to 1
+	MinusStage         string = "minus"         // Negates the sum of minus DiceRolls
+)
+
+// The in-progress roll of a DiceRoll, passed through the RollStages of a RollPipeline.
+type RollState struct {
+	roller *Roller
+	result *DiceRollResult
+	hooks  []RollAttributeHooks // Hooks of the custom rollAttributes of the DiceRoll
+	rolled []rolledDie          // Roll details of each kept die, dropped along with it
+}
+
+// Roll details of a kept die, apart from its value.
+type rolledDie struct {
+	natural  int // Natural roll, before explosions and PerDie hooks
+	extraSum int // Explosion extras added to the sum apart from the die
+	chain    int // Index of the die ExplosionChain in the DiceRollResult explosions, -1 if it didn't explode
+}
+
+// A StageRecord is the effect of a RollStage on a DiceRollResult.
+type StageRecord struct {
+	stage      string
+	sumBefore  int
+	sumAfter   int
+	diceBefore []int
+	diceAfter  []int
+}
+
+// Returns the default RollPipeline: generate, keep and drop, successes, modifier, critical total, half, minimum and minus.
+func DefaultRollPipeline() RollPipeline {
+	return RollPipeline{
+		{GenerateStage, generateStage},
+		{KeepDropStage, keepDropStage},
+		{AfterDropsStage, afterDropsStage},
+		{SuccessStage, successStage},
+		{ModifierStage, modifierStage},
+		{AfterModifierStage, afterModifierStage},
+		{CritTotalStage, critTotalStage},
+		{HalfStage, halfStage},
+		{MinimumStage, minimumStage},
+		{MinusStage, minusStage},
+	}
+}
+
+// Sets the RollPipeline performing DiceRolls. A nil pipeline restores the default one. Exact odds can't
+// be computed with a custom RollPipeline. Returns an error if invalid.
+func (roller *Roller) SetRollPipeline(pipeline RollPipeline) error {
+	if pipeline == nil {
+		roller.pipeline = nil
+		return nil
+	}
+	if pipelineErr := validateRollPipeline(pipeline); pipelineErr != nil {
+		return pipelineErr
+	}
+	roller.pipeline = slices.Clone(pipeline)
+	return nil
+}
+
+// Returns a copy of the RollPipeline performing DiceRolls.
+func (roller *Roller) RollPipeline() RollPipeline {
+	if roller.pipeline == nil {
+		return DefaultRollPipeline()
+	}
+	return slices.Clone(roller.pipeline)
+}
+
+// Validates pipeline. Returns nil if valid, an error if a RollStage has no name, a duplicate name or no Apply function.
+func validateRollPipeline(pipeline RollPipeline) error {
+	for i := range pipeline {
+		if len(pipeline[i].Name) == 0 {
+			return fmt.Errorf("invalid RollStage %d: no name", i)
+		}
+		if pipeline[i].Apply == nil {
+			return fmt.Errorf("invalid RollStage %s: no Apply function", pipeline[i].Name)
+		}
+		if pipeline.Index(pipeline[i].Name) != i {
+			return fmt.Errorf("invalid RollStage %s: duplicate name", pipeline[i].Name)
+		}
+	}
+	return nil
+}
+
+// Returns the names of the RollStages, in order.
+func (pipeline RollPipeline) Names() []string {
+	names := make([]string, len(pipeline))
+	for i := range pipeline {
+		names[i] = pipeline[i].Name
+	}
+	return names
+}
+
+// Returns the index of the RollStage named name, -1 if none.
+func (pipeline RollPipeline) Index(name string) int {
+	return slices.IndexFunc(pipeline, func(stage RollStage) bool { return stage.Name == name })
+}
+
+// Returns a copy of the RollPipeline with stages inserted before the RollStage named name. Returns an error if not found.
+func (pipeline RollPipeline) InsertBefore(name string, stages ...RollStage) (RollPipeline, error) {
+	i := pipeline.Index(name)
+	if i < 0 {
+		return nil, fmt.Errorf("unknown RollStage %s", name)
+	}
+	return slices.Insert(slices.Clone(pipeline), i, stages...), nil
+}
+
+// Returns a copy of the RollPipeline with stages inserted after the RollStage named name. Returns an error if not found.
+func (pipeline RollPipeline) InsertAfter(name string, stages ...RollStage) (RollPipeline, error) {
+	i := pipeline.Index(name)
+	if i < 0 {
+		return nil, fmt.Errorf("unknown RollStage %s", name)
+	}
+	return slices.Insert(slices.Clone(pipeline), i+1, stages...), nil
+}
+
+// Returns a copy of the RollPipeline with the RollStage named name replaced by stage. Returns an error if not found.
+func (pipeline RollPipeline) Replace(name string, stage RollStage) (RollPipeline, error) {
+	i := pipeline.Index(name)
+	if i < 0 {
+		return nil, fmt.Errorf("unknown RollStage %s", name)
+	}
+	replaced := slices.Clone(pipeline)
+	replaced[i] = stage
+	return replaced, nil
+}
+
+// Returns a copy of the RollPipeline without the RollStage named name. Returns an error if not found.
+func (pipeline RollPipeline) Remove(name string) (RollPipeline, error) {
+	i := pipeline.Index(name)
+	if i < 0 {
+		return nil, fmt.Errorf("unknown RollStage %s", name)
+	}
+	return slices.Delete(slices.Clone(pipeline), i, i+1), nil
+}
+
+// Returns the RollPipeline performing DiceRolls, without copying it.
+func (roller *Roller) rollPipeline() RollPipeline {
+	if roller.pipeline == nil {
+		return defaultRollPipeline
+	}
+	return roller.pipeline
+}
+
+// Default RollPipeline shared by Rollers without custom RollPipeline.
+var defaultRollPipeline = DefaultRollPipeline()
+
+// Applies each RollStage of pipeline to state, recording their effect in the DiceRollResult.
+func (pipeline RollPipeline) apply(state *RollState) {
+	for i := range pipeline {
+		record := StageRecord{pipeline[i].Name, state.result.sum, 0, slices.Clone(state.result.dice), nil}
+		pipeline[i].Apply(state)
+		record.sumAfter, record.diceAfter = state.result.sum, slices.Clone(state.result.dice)
+		state.result.stages = append(state.result.stages, record)
+	}
+}
+
+// Returns the DiceRoll being rolled.
+func (state *RollState) DiceRoll() DiceRoll {
+	return state.result.diceRoll
+}
+
+// Returns the kept dice.
+func (state *RollState) Dice() []int {
+	return slices.Clone(state.result.dice)
+}
+
+// Returns the current sum.
+func (state *RollState) Sum() int {
+	return state.result.sum
+}
+
+// Sets the current sum.
+func (state *RollState) SetSum(sum int) {
+	state.result.sum = sum
+}
+
+// Adds a die rolling roll to the dice and the sum, such as a Bless die.
+func (state *RollState) AddDie(roll int) {
+	state.result.dice = append(state.result.dice, roll)
+	state.result.sum += roll
+	state.rolled = append(state.rolled, rolledDie{roll, 0, -1})
+}
+
+// Changes the die at index i to roll, updating the sum. Does nothing if i is out of range.
+func (state *RollState) SetDie(i int, roll int) {
+	if i < 0 || i >= len(state.result.dice) {
+		return
+	}
+	state.result.sum += roll - state.result.dice[i]
+	state.result.dice[i] = roll
+}
+
+// Rolls a single diceSize die from the Roller random source. Returns 0 if diceSize is below 1.
+func (state *RollState) RollDie(diceSize int) int {
+	if diceSize < 1 {
+		return 0
+	}
+	return state.roller.rollDice(diceSize)
+}
+
+// Returns the name of the RollStage.
+func (record StageRecord) Stage() string {
+	return record.stage
+}
+
+// Returns the sum before the RollStage.
+func (record StageRecord) SumBefore() int {
+	return record.sumBefore
+}
+
+// Returns the sum after the RollStage.
+func (record StageRecord) SumAfter() int {
+	return record.sumAfter
+}
+
+// Returns the kept dice before the RollStage.
+func (record StageRecord) DiceBefore() []int {
+	return slices.Clone(record.diceBefore)
+}
+
+// Returns the kept dice after the RollStage.
+func (record StageRecord) DiceAfter() []int {
+	return slices.Clone(record.diceAfter)
+}
+
+// Returns true if the RollStage changed the dice or the sum.
+func (record StageRecord) Changed() bool {
+	return record.sumBefore != record.sumAfter || !slices.Equal(record.diceBefore, record.diceAfter)
+}
+
+// Human readable StageRecord string, such as "modifier: 7 -> 12".
+func (record StageRecord) String() string {
+	recordStr := fmt.Sprintf("%s: %d -> %d", record.stage, record.sumBefore, record.sumAfter)
+	if !slices.Equal(record.diceBefore, record.diceAfter) {
+		recordStr += fmt.Sprintf(", dice %v -> %v", record.diceBefore, record.diceAfter)
+	}
+	return recordStr
+}
+
+// Rolls the dice, custom rollAttributes may change the dice rolled.
+func generateStage(state *RollState) {
+	diceRoll := state.result.diceRoll
+	diceRoll.diceAmmount, diceRoll.diceSize = diceRoll.hookedDice(state.hooks)
+	state.roller.generateRolls(diceRoll, state)
+}
+
+// Applies keep and drop rules in order.
+func keepDropStage(state *RollState) {
+	for _, rule := range state.result.diceRoll.keepDropRules() {
+		high, low := rule.dropCounts(len(state.result.dice))
+		dropHigh(state, high)
+		dropLow(state, low)
+	}
+}
+
+// Applies AfterDrops hooks.
+func afterDropsStage(state *RollState) {
+	state.result.sum = applyAfterDropsHooks(state.hooks, state.result.dice, state.result.sum)
+}
+
+// Counts net successes of dice pools.
+func successStage(state *RollState) {
+	if rule := state.result.diceRoll.successRule(); rule != nil {
+		countSuccesses(state.result, *rule)
+	}
+}
+
+// Adds the modifier, it adds successes to dice pools.
+func modifierStage(state *RollState) {
+	state.result.sum = applyModifier(state.result.diceRoll, state.result.sum)
+}
+
+// Applies AfterModifier hooks.
+func afterModifierStage(state *RollState) {
+	state.result.sum = applyAfterModifierHooks(state.hooks, state.result.sum)
+}
+
+// Doubles the sum of critical DiceRolls with the CritDoubleTotal policy.
+func critTotalStage(state *RollState) {
+	state.result.sum = state.roller.applyCritTotal(state.result.diceRoll, state.result.sum)
+}
+
+// Halves the sum of half DiceRolls.
+func halfStage(state *RollState) {
+	state.result.sum = applyHalf(state.result.diceRoll, state.result.sum)
+}
+
+// Raises the sum to 1.
+func minimumStage(state *RollState) {
+	state.result.sum = applyMinimum(state.result.diceRoll, state.result.sum)
+}
+
+// Negates the sum of minus DiceRolls.
+func minusStage(state *RollState) {
+	state.result.sum = applyMinus(state.result.diceRoll, state.result.sum)
+}
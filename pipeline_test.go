@@ -0,0 +1,147 @@
+package diceroller
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDefaultRollPipeline(t *testing.T) {
+	wantedNames := []string{GenerateStage, KeepDropStage, AfterDropsStage, SuccessStage, ModifierStage,
+		AfterModifierStage, CritTotalStage, HalfStage, MinimumStage, MinusStage}
+	if names := NewRoller(nil).RollPipeline().Names(); !slices.Equal(names, wantedNames) {
+		t.Fatalf("Default RollPipeline is %v, expected %v", names, wantedNames)
+	}
+
+	// Each stage records its effect
+	results, _ := NewRoller(maxSource{}).PerformRollArgs("half", "4d6dl1-4")
+	stages := results[0].results[0].Stages()
+	if len(stages) != len(wantedNames) {
+		t.Fatalf("Recorded %d stages, expected %d", len(stages), len(wantedNames))
+	}
+	wantedRecords := []struct {
+		stage      string
+		sumBefore  int
+		sumAfter   int
+		diceBefore []int
+		diceAfter  []int
+		str        string
+	}{
+		{GenerateStage, 0, 24, []int{}, []int{6, 6, 6, 6}, "generate: 0 -> 24, dice [] -> [6 6 6 6]"},
+		{KeepDropStage, 24, 18, []int{6, 6, 6, 6}, []int{6, 6, 6}, "keepdrop: 24 -> 18, dice [6 6 6 6] -> [6 6 6]"},
+		{ModifierStage, 18, 14, []int{6, 6, 6}, []int{6, 6, 6}, "modifier: 18 -> 14"},
+		{HalfStage, 14, 7, []int{6, 6, 6}, []int{6, 6, 6}, "half: 14 -> 7"},
+		{MinimumStage, 7, 7, []int{6, 6, 6}, []int{6, 6, 6}, "minimum: 7 -> 7"},
+	}
+	for i := range wantedRecords {
+		record := stages[slices.IndexFunc(stages, func(record StageRecord) bool { return record.Stage() == wantedRecords[i].stage })]
+		if record.SumBefore() != wantedRecords[i].sumBefore || record.SumAfter() != wantedRecords[i].sumAfter ||
+			!slices.Equal(record.DiceBefore(), wantedRecords[i].diceBefore) || !slices.Equal(record.DiceAfter(), wantedRecords[i].diceAfter) {
+			t.Fatalf("Stage %s recorded %s, expected %s", wantedRecords[i].stage, record, wantedRecords[i].str)
+		}
+		if record.String() != wantedRecords[i].str {
+			t.Fatalf("Stage %s string is %s, expected %s", wantedRecords[i].stage, record, wantedRecords[i].str)
+		}
+		if record.Changed() != (wantedRecords[i].sumBefore != wantedRecords[i].sumAfter) {
+			t.Fatalf("Stage %s changed is %t", wantedRecords[i].stage, record.Changed())
+		}
+	}
+}
+
+func TestCustomRollPipeline(t *testing.T) {
+	roller := NewRoller(maxSource{})
+	pipeline := roller.RollPipeline()
+
+	// Bless die added to the sum
+	bless := RollStage{"bless", func(state *RollState) { state.SetSum(state.Sum() + state.RollDie(4)) }}
+	pipeline, insertErr := pipeline.InsertAfter(ModifierStage, bless)
+	if insertErr != nil {
+		t.Fatalf("Inserting after %s returned an error: %s", ModifierStage, insertErr.Error())
+	}
+
+	// Per-die ceiling, lowering dice above 5
+	ceiling := RollStage{"ceiling", func(state *RollState) {
+		for i, roll := range state.Dice() {
+			state.SetDie(i, min(roll, 5))
+		}
+	}}
+	pipeline, _ = pipeline.InsertBefore(KeepDropStage, ceiling)
+
+	// Logging every die of a wrapped stage
+	logged := make([]int, 0)
+	keepDrop := pipeline[pipeline.Index(KeepDropStage)]
+	pipeline, _ = pipeline.Replace(KeepDropStage, RollStage{KeepDropStage, func(state *RollState) {
+		logged = append(logged, state.Dice()...)
+		keepDrop.Apply(state)
+	}})
+
+	// Minimum 1 applied before the modifier
+	pipeline, _ = pipeline.Remove(MinimumStage)
+	pipeline, _ = pipeline.InsertBefore(ModifierStage, RollStage{MinimumStage, minimumStage})
+
+	if pipelineErr := roller.SetRollPipeline(pipeline); pipelineErr != nil {
+		t.Fatalf("Valid RollPipeline returned an error: %s", pipelineErr.Error())
+	}
+
+	if sum := roller.PerformRollArgsAndSum("3d6dl1+2"); sum != 16 {
+		t.Fatalf("3d6dl1+2 with custom RollPipeline sum is %d, expected 16", sum)
+	}
+	if !slices.Equal(logged, []int{5, 5, 5}) {
+		t.Fatalf("Logged dice %v, expected [5 5 5]", logged)
+	}
+	if sum := roller.PerformRollArgsAndSum("1d4-10"); sum != -2 {
+		t.Fatalf("1d4-10 with custom RollPipeline sum is %d, expected -2", sum)
+	}
+	results, _ := roller.PerformRollArgs("1d6")
+	if stages := results[0].results[0].Stages(); len(stages) != len(pipeline) || stages[1].Stage() != "ceiling" || stages[1].String() != "ceiling: 6 -> 5, dice [6] -> [5]" {
+		t.Fatalf("Custom RollPipeline recorded stages %v", stages)
+	}
+
+	// Custom RollPipelines can't be computed exactly, nil restores the default
+	if _, distErrs := roller.RollArgsDistribution("1d6"); len(distErrs) == 0 {
+		t.Fatalf("Distribution with a custom RollPipeline returned no error")
+	}
+	roller.SetRollPipeline(nil)
+	if sum := roller.PerformRollArgsAndSum("3d6dl1+2"); sum != 14 {
+		t.Fatalf("3d6dl1+2 with default RollPipeline sum is %d, expected 14", sum)
+	}
+	if _, distErrs := roller.RollArgsDistribution("1d6"); len(distErrs) > 0 {
+		t.Fatalf("Distribution with the default RollPipeline returned errors %v", distErrs)
+	}
+}
+
+func TestInvalidRollPipelines(t *testing.T) {
+	pipeline := DefaultRollPipeline()
+	if _, insertErr := pipeline.InsertAfter("unknown", RollStage{"bless", generateStage}); insertErr == nil {
+		t.Fatalf("Inserting after an unknown RollStage returned no error")
+	}
+	if _, insertErr := pipeline.InsertBefore("unknown", RollStage{"bless", generateStage}); insertErr == nil {
+		t.Fatalf("Inserting before an unknown RollStage returned no error")
+	}
+	if _, replaceErr := pipeline.Replace("unknown", RollStage{"bless", generateStage}); replaceErr == nil {
+		t.Fatalf("Replacing an unknown RollStage returned no error")
+	}
+	if _, removeErr := pipeline.Remove("unknown"); removeErr == nil {
+		t.Fatalf("Removing an unknown RollStage returned no error")
+	}
+
+	invalidPipelines := []RollPipeline{
+		{{"", generateStage}},
+		{{GenerateStage, nil}},
+		{{GenerateStage, generateStage}, {GenerateStage, generateStage}},
+	}
+	roller := NewRoller(nil)
+	for i := range invalidPipelines {
+		if pipelineErr := roller.SetRollPipeline(invalidPipelines[i]); pipelineErr == nil {
+			t.Fatalf("Invalid RollPipeline %v returned no error", invalidPipelines[i].Names())
+		}
+	}
+	if roller.pipeline != nil {
+		t.Fatalf("Invalid RollPipelines changed the Roller RollPipeline")
+	}
+
+	// An empty pipeline rolls nothing
+	roller.SetRollPipeline(RollPipeline{})
+	if sum := roller.PerformRollArgsAndSum("2d6+3"); sum != 0 {
+		t.Fatalf("2d6+3 with an empty RollPipeline sum is %d, expected 0", sum)
+	}
+}
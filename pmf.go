@@ -0,0 +1,180 @@
+package diceroller
+
+import (
+	"math"
+	"slices"
+)
+
+// A pmf is a probability mass function over consecutive integer values. Sub-distributions,
+// whose total is less than 1, represent the outcomes of an event such as a critical hit.
+type pmf struct {
+	min int       // Value of the first probability
+	p   []float64 // Probabilities of min, min+1, ...
+}
+
+// Returns a pmf where value has a probability of 1.
+func pointPMF(value int) pmf {
+	return pmf{value, []float64{1}}
+}
+
+// Returns the highest value of the pmf.
+func (dist pmf) max() int {
+	return dist.min + len(dist.p) - 1
+}
+
+// Returns the probability of value.
+func (dist pmf) probability(value int) float64 {
+	if value < dist.min || value > dist.max() {
+		return 0
+	}
+	return dist.p[value-dist.min]
+}
+
+// Returns the total probability of the pmf, 1 unless it is a sub-distribution.
+func (dist pmf) total() (total float64) {
+	for i := range dist.p {
+		total += dist.p[i]
+	}
+	return
+}
+
+// Returns the pmf with every probability multiplied by factor.
+func (dist pmf) scale(factor float64) pmf {
+	scaled := pmf{dist.min, make([]float64, len(dist.p))}
+	for i := range dist.p {
+		scaled.p[i] = dist.p[i] * factor
+	}
+	return scaled
+}
+
+// Returns the pmf with every value moved by offset.
+func (dist pmf) shift(offset int) pmf {
+	return pmf{dist.min + offset, dist.p}
+}
+
+// Returns the pmf of f(X). Values mapped to the same result add up.
+func (dist pmf) transform(f func(value int) int) pmf {
+	values := make([]int, len(dist.p))
+	for i := range dist.p {
+		values[i] = f(dist.min + i)
+	}
+	return accumulate(values, dist.p)
+}
+
+// Returns the pmf where each of values has the matching probability. Repeated values add up.
+func accumulate(values []int, probabilities []float64) pmf {
+	if len(values) == 0 {
+		return pmf{}
+	}
+
+	accumulated := pmf{slices.Min(values), make([]float64, slices.Max(values)-slices.Min(values)+1)}
+	for i := range values {
+		accumulated.p[values[i]-accumulated.min] += probabilities[i]
+	}
+	return accumulated.trim()
+}
+
+// Returns the pointwise sum of both pmfs, the union of two disjoint sub-distributions.
+func (dist pmf) add(other pmf) pmf {
+	if len(dist.p) == 0 {
+		return other
+	}
+	if len(other.p) == 0 {
+		return dist
+	}
+
+	sum := pmf{min(dist.min, other.min), make([]float64, max(dist.max(), other.max())-min(dist.min, other.min)+1)}
+	for i := range dist.p {
+		sum.p[dist.min+i-sum.min] += dist.p[i]
+	}
+	for i := range other.p {
+		sum.p[other.min+i-sum.min] += other.p[i]
+	}
+	return sum
+}
+
+// Returns the pointwise difference of both pmfs, other being a sub-distribution of dist.
+func (dist pmf) subtract(other pmf) pmf {
+	difference := dist.add(other.scale(-1))
+	for i := range difference.p {
+		// Rounding errors can't make probabilities negative
+		difference.p[i] = math.Max(difference.p[i], 0)
+	}
+	return difference.trim()
+}
+
+// Returns the pmf without leading and trailing zero probabilities.
+func (dist pmf) trim() pmf {
+	start, end := 0, len(dist.p)
+	for start < end && dist.p[start] == 0 {
+		start++
+	}
+	for end > start && dist.p[end-1] == 0 {
+		end--
+	}
+	return pmf{dist.min + start, dist.p[start:end]}
+}
+
+// Returns the pmf of X+Y for independent X and Y.
+func convolve(dist pmf, other pmf) pmf {
+	if len(dist.p) == 0 || len(other.p) == 0 {
+		return pmf{}
+	}
+
+	// Point pmfs only move and scale the other pmf
+	if len(dist.p) == 1 {
+		return other.scale(dist.p[0]).shift(dist.min)
+	}
+	if len(other.p) == 1 {
+		return dist.scale(other.p[0]).shift(other.min)
+	}
+
+	sum := pmf{dist.min + other.min, make([]float64, len(dist.p)+len(other.p)-1)}
+	for i := range dist.p {
+		if dist.p[i] == 0 {
+			continue
+		}
+		for j := range other.p {
+			sum.p[i+j] += dist.p[i] * other.p[j]
+		}
+	}
+	return sum
+}
+
+// Returns the pmf of the sum of n independent copies of X.
+func (dist pmf) convolvePower(n int) pmf {
+	result := pointPMF(0)
+	for power := dist; n > 0; n /= 2 {
+		if n%2 == 1 {
+			result = convolve(result, power)
+		}
+		if n > 1 {
+			power = convolve(power, power)
+		}
+	}
+	return result
+}
+
+// Returns the pmf of f(X, Y) for independent X and Y. Returns an error if f does.
+func combine(dist pmf, other pmf, f func(value int, value2 int) (int, error)) (pmf, error) {
+	values, probabilities := make([]int, 0), make([]float64, 0)
+	for i := range dist.p {
+		for j := range other.p {
+			if dist.p[i] == 0 || other.p[j] == 0 {
+				continue
+			}
+			value, err := f(dist.min+i, other.min+j)
+			if err != nil {
+				return pmf{}, err
+			}
+			values = append(values, value)
+			probabilities = append(probabilities, dist.p[i]*other.p[j])
+		}
+	}
+
+	if len(values) > 0 && slices.Max(values)-slices.Min(values) >= maxDistributionSupport {
+		return pmf{}, errDistributionTooLarge
+	}
+
+	return accumulate(values, probabilities), nil
+}
@@ -0,0 +1,50 @@
+package diceroller
+
+import (
+	"math"
+	"testing"
+)
+
+// Uniform pmf of a die of diceSize faces
+func diePMF(diceSize int) pmf {
+	die := pmf{1, make([]float64, diceSize)}
+	for i := range die.p {
+		die.p[i] = 1 / float64(diceSize)
+	}
+	return die
+}
+
+func TestConvolvePower(t *testing.T) {
+	sum := diePMF(6).convolvePower(3)
+	if sum.min != 3 || sum.max() != 18 {
+		t.Fatalf("3d6 pmf ranges from %d to %d", sum.min, sum.max())
+	}
+	if math.Abs(sum.probability(10)-27.0/216) > 1e-12 || math.Abs(sum.total()-1) > 1e-12 {
+		t.Fatalf("3d6 probability of 10 is %f, total %f", sum.probability(10), sum.total())
+	}
+	if point := diePMF(6).convolvePower(0); point.min != 0 || point.probability(0) != 1 {
+		t.Fatal("Sum of no dice isn't always 0")
+	}
+}
+
+func TestSubtractAndAdd(t *testing.T) {
+	die := diePMF(4)
+	high := pmf{3, []float64{0.25, 0.25}}
+	low := die.subtract(high)
+	if low.min != 1 || low.max() != 2 || math.Abs(low.total()-0.5) > 1e-12 {
+		t.Fatalf("Sub-distribution of low d4 rolls is %v", low)
+	}
+	if sum := low.add(high); sum.min != 1 || sum.max() != 4 || math.Abs(sum.total()-1) > 1e-12 {
+		t.Fatalf("Union of d4 sub-distributions is %v", sum)
+	}
+}
+
+func TestCombine(t *testing.T) {
+	product, _ := combine(diePMF(2), pointPMF(3), func(value int, value2 int) (int, error) { return value * value2, nil })
+	if product.min != 3 || product.max() != 6 || product.probability(3) != 0.5 || product.probability(4) != 0 {
+		t.Fatalf("d2 times 3 pmf is %v", product)
+	}
+	if transformed := diePMF(4).transform(func(value int) int { return value / 2 }); transformed.probability(1) != 0.5 {
+		t.Fatalf("d4 halved pmf is %v", transformed)
+	}
+}
@@ -0,0 +1,74 @@
+package diceroller
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Allowed reroll strings in a RollArg.
+const (
+	rerollStr     string = "r"
+	rerollOnceStr string = "ro"
+)
+
+// Max amount of rerolls for a single die, to avoid endless reroll until loops.
+const maxRerollChainLength int = 100
+
+// A rerollRule describes which dice of a DiceRoll are rerolled.
+type rerollRule struct {
+	once      bool          // Reroll once and keep the new roll, otherwise reroll until it doesn't match
+	threshold rollThreshold // Dice matching the threshold are rerolled
+}
+
+// A rerolled die face and the roll that replaced it.
+type RerolledDie struct {
+	face        int
+	replacement int
+}
+
+// Returns the rerolled face.
+func (die RerolledDie) Face() int {
+	return die.face
+}
+
+// Returns the roll replacing the rerolled face.
+func (die RerolledDie) Replacement() int {
+	return die.replacement
+}
+
+// Parses a reroll RollArg slice such as "ro<2". Returns a rerollRule if valid, an error if invalid.
+func parseRerollRule(modeStr string, thresholdStr string) (*rerollRule, error) {
+	modeStr = strings.ToLower(modeStr)
+	if modeStr != rerollStr && modeStr != rerollOnceStr {
+		return nil, fmt.Errorf("invalid reroll: %s", modeStr)
+	}
+
+	threshold, argErr := parseRollThreshold(thresholdStr)
+	if argErr != nil {
+		return nil, argErr
+	}
+
+	return &rerollRule{modeStr == rerollOnceStr, *threshold}, nil
+}
+
+// Reroll string, such as "ro<2".
+func (rule rerollRule) String() string {
+	rerollRuleStr := rerollStr
+	if rule.once {
+		rerollRuleStr = rerollOnceStr
+	}
+	return rerollRuleStr + rule.threshold.String()
+}
+
+// Validates a rerollRule for a diceSize die. Returns nil if valid, an error if invalid.
+func validateRerollRule(rule *rerollRule, diceSize int) error {
+	if rule != nil && !rule.once && rule.threshold.matchingFaces(diceSize) == diceSize {
+		return fmt.Errorf("invalid reroll %s, rerolls every face", rule)
+	}
+	return nil
+}
+
+// Human readable RerolledDie string, such as "1->4".
+func (rerolled RerolledDie) String() string {
+	return fmt.Sprintf("%d->%d", rerolled.face, rerolled.replacement)
+}
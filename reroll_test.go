@@ -0,0 +1,104 @@
+package diceroller
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Valid reroll Roll Args
+var validRerollRollArgs = []string{
+	"2d6r1",
+	"1d20ro1",
+	"4d6r<3",
+	"3d8RO<=2+1",
+	"2d10r=10!",
+	"8d6ro1!!>5-2",
+	"1d6ro>=1"}
+
+// Invalid reroll Roll Args
+var invalidRerollRollArgs = []string{
+	"1d6r<7",
+	"1d6r>=1",
+	"1d6r",
+	"1d6rr1",
+	"1d6or1",
+	"1d6!r1"}
+
+func TestParseValidRerollRollArgs(t *testing.T) {
+	for i := range validRerollRollArgs {
+		diceRoll, argErr := parseRollArg(validRerollRollArgs[i])
+		if argErr != nil {
+			validArgParsingError(argErr, t)
+		}
+		if diceRoll.rerollRule() == nil {
+			t.Fatalf("RollArg %s parsed without a rerollRule", validRerollRollArgs[i])
+		}
+	}
+}
+
+func TestParseInvalidRerollRollArgs(t *testing.T) {
+	for i := range invalidRerollRollArgs {
+		if _, argErr := parseRollArg(invalidRerollRollArgs[i]); argErr == nil {
+			invalidArgParsingError(invalidRerollRollArgs[i], t)
+		}
+	}
+}
+
+func TestRerollUntil(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		results, _ := PerformRollArgs("10d6r<3")
+		result := results[0].results[0]
+
+		for d := range result.dice {
+			if result.dice[d] < 3 {
+				t.Fatalf("10d6r<3 kept %d, wanted >= 3", result.dice[d])
+			}
+		}
+		for r := range result.rerolled {
+			if result.rerolled[r].face >= 3 {
+				t.Fatalf("10d6r<3 rerolled %d, wanted < 3", result.rerolled[r].face)
+			}
+		}
+	}
+}
+
+func TestRerollChainCap(t *testing.T) {
+	// Max source always rolls the highest face, reroll until never stops on its own
+	wantedRerolls := []struct {
+		rollArgs []string
+		rerolls  int
+	}{
+		{[]string{"1d6r6"}, maxRerollChainLength},
+		{[]string{"1d6ro6"}, 1},
+		{[]string{"2d6ro>5"}, 2},
+		{[]string{"1d6r1"}, 0},
+		{[]string{"adv", "1d6ro=6"}, 2},
+	}
+
+	for i := range wantedRerolls {
+		results, _ := NewRoller(maxSource{}).PerformRollArgs(wantedRerolls[i].rollArgs...)
+		if rerolled := len(results[0].results[0].rerolled); rerolled != wantedRerolls[i].rerolls {
+			t.Fatalf("%s with max source rerolled %d times, wanted %d", wantedRerolls[i].rollArgs, rerolled, wantedRerolls[i].rerolls)
+		}
+	}
+}
+
+func TestRerollRuleString(t *testing.T) {
+	for i := range validRerollRollArgs {
+		diceRoll, _ := parseRollArg(validRerollRollArgs[i])
+		reparsed, argErr := parseRollArg(diceRoll.String())
+		if argErr != nil {
+			t.Fatalf("DiceRoll %s string is not a valid RollArg: %s", diceRoll, argErr.Error())
+		}
+		if reparsed.String() != diceRoll.String() {
+			t.Fatalf("DiceRoll = %s, wanted %s", reparsed, diceRoll)
+		}
+	}
+}
+
+func FuzzRerollRollArg(f *testing.F) {
+	f.Add(3, 6, "<3")
+	f.Fuzz(func(t *testing.T, diceAmmount int, diceSize int, threshold string) {
+		PerformRollArgs(fmt.Sprintf("%dd%dr%s", diceAmmount, diceSize, threshold))
+	})
+}
@@ -1,45 +1,80 @@
 package diceroller
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
-
-	"golang.org/x/exp/maps"
 )
 
-// RollArg regex
-const rollArgFormat string = `^([+-])?(\d+)?[dD](\d+)([+-](\d+))?$`
-
 // Attributes regex
 const rollAttribsFormat string = `^[a-z]+$`
 
 // Maximum allowed RollArg length
 const maxAllowedRollArgLength int = 5
 
-// Parses a RollArg array. Returns a DiceRoll array for valid RollArgs, an
-// error array for invalid ones.
+// Parses a RollArg array. Returns a DiceRoll array for valid RollArgs, a
+// ParseError array for invalid ones.
 func parseRollArgs(rollArgs ...string) (rollingExpressions []rollingExpression, errors []error) {
 	// We're building rollingExpressions along with their rollAttributes
 	rollExpr := newRollingExpression()
 	attribs := newRollAttributes()
 
+	// DiceRolls and formulas of the last parsed RollArg, tagged by a following damage type
+	lastDiceRolls, lastFormulas := 0, 0
+
 	for i := range rollArgs {
-		if rollAttrib := checkForRollAttribute(rollArgs[i]); rollAttrib != 0 {
+		rollAttrib := checkForRollAttribute(rollArgs[i])
+		keepDropAlias := checkForKeepDropAlias(rollArgs[i])
+
+		if damageType := checkForDamageType(rollArgs[i]); damageType != DamageUntyped {
+			diceRolls := rollExpr.diceRolls[len(rollExpr.diceRolls)-lastDiceRolls:]
+			formulas := rollExpr.formulas[len(rollExpr.formulas)-lastFormulas:]
+			if err := tagDamageType(damageType, diceRolls, formulas); err != nil {
+				errors = append(errors, newArgParseError(i, rollArgs[i], err))
+			}
+			lastDiceRolls, lastFormulas = 0, 0
+		} else if rollAttrib != 0 || keepDropAlias != nil {
+			lastDiceRolls, lastFormulas = 0, 0
+
 			// Start a new rolling expression after a dice roll sequence ends
-			if len(rollExpr.diceRolls) > 0 {
+			if !rollExpr.isEmpty() {
 				rollingExpressions = append(rollingExpressions, *rollExpr)
 				rollExpr = newRollingExpression()
 				attribs = newRollAttributes()
 			}
-			// Apply the rollAttribute to diceRolls
-			attribs.setRollAttrib(rollAttrib)
-		} else if diceRoll, err := parseRollArg(rollArgs[i]); err == nil {
-			diceRoll.rollAttribs.setRollAttrib(maps.Keys(attribs.attribs)...)
-			rollExpr.diceRolls = append(rollExpr.diceRolls, *diceRoll)
+			// Attack and damage separators set the rolling expression kind
+			switch rollAttrib {
+			case HitAttrib:
+				rollExpr.kind = attackExpression
+			case DmgAttrib:
+				rollExpr.kind = damageExpression
+			}
+			// Apply the rollAttribute or keepDropRule alias to diceRolls
+			if rollAttrib != 0 {
+				attribs.setRollAttrib(rollAttrib)
+			} else {
+				attribs.keepDrop = append(attribs.keepDrop, *keepDropAlias)
+			}
+		} else if diceRolls, formula, err := parseRollArgExpression(rollArgs[i]); err == nil {
+			for d := range diceRolls {
+				diceRolls[d].rollAttribs.mergeRollAttribs(attribs)
+			}
+			rollExpr.diceRolls = append(rollExpr.diceRolls, diceRolls...)
+			lastDiceRolls, lastFormulas = len(diceRolls), 0
+
+			if formula != nil {
+				for d := range formula.diceRolls {
+					formula.diceRolls[d].rollAttribs.mergeRollAttribs(attribs)
+				}
+				rollExpr.formulas = append(rollExpr.formulas, *formula)
+				lastFormulas = 1
+			}
 		} else {
-			errors = append(errors, err)
+			lastDiceRolls, lastFormulas = 0, 0
+			errors = append(errors, newArgParseError(i, rollArgs[i], err))
 		}
 	}
 
@@ -49,42 +84,139 @@ func parseRollArgs(rollArgs ...string) (rollingExpressions []rollingExpression,
 }
 
 // Checks if the rollArg is a rollAttribute. Returns the rollAttribute value if it matches, otherwise zero.
-func checkForRollAttribute(rollArg string) rollAttribute {
-	var rollAttrib rollAttribute = 0
+func checkForRollAttribute(rollArg string) RollAttribute {
+	var rollAttrib RollAttribute = 0
 	attribRegEx := regexp.MustCompile(rollAttribsFormat)
 	if attribRegEx.MatchString(strings.ToLower(rollArg)) {
-		rollAttrib = rollAttributeMap[rollArg]
+		rollAttrib = lookupRollAttribute(rollArg)
 	}
 	return rollAttrib
 }
 
-// Parses rollArg. Returns a DiceRoll if valid, an error if invalid.
+// Parses rollArg into a single DiceRoll. Returns a DiceRoll if valid, an error if invalid
+// or if rollArg needs more than one DiceRoll.
 func parseRollArg(rollArg string) (*DiceRoll, error) {
-	// Validate rollArg format
-	rollArgregex := regexp.MustCompile(rollArgFormat)
-	if !rollArgregex.MatchString(rollArg) {
-		return nil, fmt.Errorf("invalid RollArg: %s", rollArg)
+	diceRolls, formula, argErr := parseRollArgExpression(rollArg)
+	if argErr != nil {
+		return nil, argErr
+	}
+
+	if len(diceRolls) != 1 || formula != nil {
+		return nil, fmt.Errorf("invalid RollArg: %s is not a single DiceRoll", rollArg)
 	}
 
-	// Parse rollArg into slices using the regex matches
-	matches := rollArgregex.FindStringSubmatch(rollArg)
+	return &diceRolls[0], nil
+}
 
-	var diceAmmount, diceSize, modifier = 0, 0, 0
-	var rollAttributes *rollAttributes = newRollAttributes()
+// Parses a rollArg arithmetic expression. Additive expressions such as "2d6+1d4+3" are reduced to
+// DiceRolls, constants being added to the first DiceRoll modifier. Other expressions, such as
+// "(1d8+2)*2", return a rollFormula. Returns a ParseError if invalid.
+func parseRollArgExpression(rollArg string) (diceRolls []DiceRoll, formula *rollFormula, argErr error) {
+	tokens, argErr := tokenizeRollArg(rollArg)
+	if argErr != nil {
+		return nil, nil, withRollArg(argErr, rollArg)
+	}
+
+	// Legacy format, a leading minus negates the DiceRoll modifier included
+	if isLegacyDiceRollTokens(tokens) {
+		diceRoll, argErr := parseLegacyDiceRollTokens(tokens)
+		if argErr != nil {
+			return nil, nil, withRollArg(argErr, rollArg)
+		}
+		return []DiceRoll{*diceRoll}, nil, nil
+	}
+
+	parser := &rollArgParser{tokens, 0, nil}
+	root, argErr := parser.parse()
+	if argErr != nil {
+		return nil, nil, withRollArg(argErr, rollArg)
+	}
+
+	if len(parser.diceRolls) == 0 {
+		return nil, nil, withRollArg(fmt.Errorf("no dice"), rollArg)
+	}
+
+	// Reduce additive expressions to DiceRolls
+	if diceRolls, reduced := reduceAdditiveFormula(root, parser.diceRolls); reduced {
+		for i := range diceRolls {
+			if diceErr := validateDiceRoll(diceRolls[i]); diceErr != nil {
+				return nil, nil, withRollArg(diceErr, rollArg)
+			}
+		}
+		return diceRolls, nil, nil
+	}
+
+	return nil, &rollFormula{root, parser.diceRolls, rollArg}, nil
+}
+
+// Parses legacy format tokens. Returns a DiceRoll if valid, a ParseError if invalid.
+func parseLegacyDiceRollTokens(tokens []rollToken) (*DiceRoll, error) {
+	minus := false
 
 	// Parse minus sign
-	if len(matches[1]) > 0 {
-		if strings.EqualFold(matches[1], "-") {
-			rollAttributes.setRollAttrib(minusAttrib)
+	if tokens[0].kind == plusToken || tokens[0].kind == minusToken {
+		minus = tokens[0].kind == minusToken
+		tokens = tokens[1:]
+	}
+
+	diceRoll, argErr := parseDiceToken(tokens[0])
+	if argErr != nil {
+		return nil, newParseError(tokens[0].offset, argErr)
+	}
+
+	if minus {
+		diceRoll.rollAttribs.setRollAttrib(MinusAttrib)
+	}
+
+	// Parse modifier
+	if len(tokens) == 3 {
+		if value, argErr := parseRollArgSlice(tokens[1].text + tokens[2].text); argErr == nil {
+			diceRoll.modifier = value
+		} else {
+			return nil, newParseError(tokens[1].offset, &ValidationError{BadModifier, diceRoll.String(), argErr})
 		}
 	}
 
+	validDiceRoll, diceErr := NewDiceRollWithAttribs(diceRoll.diceAmmount, diceRoll.diceSize, diceRoll.modifier, diceRoll.rollAttribs)
+	if diceErr != nil {
+		return nil, newParseError(tokens[0].offset, diceErr)
+	}
+	return validDiceRoll, nil
+}
+
+// Returns err as a ParseError of the RollArg at index arg. Misspelled rollAttributes get a suggestion.
+func newArgParseError(arg int, rollArg string, err error) *ParseError {
+	parseErr := withRollArg(err, rollArg)
+	parseErr.Arg = arg
+	if parseErr.Offset == 0 && regexp.MustCompile(rollAttribsFormat).MatchString(strings.ToLower(rollArg)) {
+		parseErr.Suggestion = suggestRollArg(rollArg)
+	}
+	return parseErr
+}
+
+// Returns err as a ParseError of rollArg, at offset 0 unless err is a ParseError already.
+func withRollArg(err error, rollArg string) *ParseError {
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		parseErr = newParseError(0, err)
+	}
+	parseErr.RollArg = rollArg
+	return parseErr
+}
+
+// Parses a diceToken. Returns an unvalidated DiceRoll without modifier, or an error if invalid.
+func parseDiceToken(token rollToken) (*DiceRoll, error) {
+	matches := token.matches
+
+	var diceAmmount, diceSize = 0, 0
+	var rollAttributes *rollAttributes = newRollAttributes()
+
 	// Parse dice ammount
-	if len(matches[2]) > 0 {
-		if value, argErr := parseRollArgSlice(matches[2]); argErr == nil {
+	if len(matches[1]) > 0 {
+		if value, argErr := parseRollArgSlice(matches[1]); argErr == nil {
 			diceAmmount = value
 		} else {
-			return nil, argErr
+			return nil, &ValidationError{TooManyDice, token.text, argErr}
 		}
 	} else {
 		// dY syntax.
@@ -92,22 +224,226 @@ func parseRollArg(rollArg string) (*DiceRoll, error) {
 	}
 
 	// Parse dice size
-	if value, argErr := parseRollArgSlice(matches[3]); argErr == nil {
+	if value, argErr := parseRollArgSlice(matches[2]); argErr == nil {
 		diceSize = value
 	} else {
-		return nil, argErr
+		return nil, &ValidationError{BadSize, token.text, argErr}
 	}
 
-	// Parse modifier
-	if len(matches[4]) > 0 {
-		if value, argErr := parseRollArgSlice(matches[4]); argErr == nil {
-			modifier = value
+	// Parse rerolls
+	if len(matches[3]) > 0 {
+		if rule, argErr := parseRerollRule(matches[3], matches[4]); argErr == nil {
+			rollAttributes.reroll = rule
+		} else {
+			return nil, argErr
+		}
+	}
+
+	// Parse exploding dice
+	if len(matches[5]) > 0 {
+		if rule, argErr := parseExplodeRule(matches[5], matches[6]); argErr == nil {
+			rollAttributes.explode = rule
+		} else {
+			return nil, argErr
+		}
+	}
+
+	// Parse keep or drop
+	if len(matches[7]) > 0 {
+		if rule, argErr := parseKeepDropRule(matches[7], matches[8]); argErr == nil {
+			rollAttributes.keepDrop = append(rollAttributes.keepDrop, *rule)
 		} else {
 			return nil, argErr
 		}
 	}
 
-	return NewDiceRollWithAttribs(diceAmmount, diceSize, modifier, rollAttributes)
+	// Parse success counting
+	if len(matches[9]) > 0 {
+		if rule, argErr := parseSuccessRule(matches[9], matches[10]); argErr == nil {
+			rollAttributes.success = rule
+		} else {
+			return nil, argErr
+		}
+	}
+
+	// Parse critical range
+	if len(matches[11]) > 0 || len(matches[12]) > 0 {
+		if crits, argErr := parseCritRange(matches[11], matches[12]); argErr == nil {
+			rollAttributes.crits = crits
+		} else {
+			return nil, argErr
+		}
+	}
+
+	return &DiceRoll{diceAmmount, diceSize, 0, rollAttributes}, nil
+}
+
+// Reduces an additive formula to DiceRolls, constants being added to the first positive DiceRoll
+// modifier and negative DiceRolls getting the minus rollAttribute. Returns false if it can't be reduced.
+func reduceAdditiveFormula(root *formulaNode, formulaDiceRolls []DiceRoll) (diceRolls []DiceRoll, reduced bool) {
+	terms := make([]signedFormulaNode, 0)
+	if !root.additiveTerms(1, &terms) {
+		return nil, false
+	}
+
+	constant := 0
+	firstPositive := -1
+	for i := range terms {
+		if terms[i].node.kind == numberToken {
+			constant += terms[i].sign * terms[i].node.value
+		} else if terms[i].sign > 0 && firstPositive < 0 {
+			firstPositive = i
+		}
+	}
+
+	// Constants can't be added to negative DiceRolls without changing their meaning
+	if constant != 0 && firstPositive < 0 {
+		return nil, false
+	}
+
+	for i := range terms {
+		if terms[i].node.kind == diceToken {
+			diceRoll := formulaDiceRolls[terms[i].node.value]
+			if terms[i].sign < 0 {
+				diceRoll.rollAttribs.setRollAttrib(MinusAttrib)
+			} else if i == firstPositive {
+				diceRoll.modifier = constant
+			}
+			diceRolls = append(diceRolls, diceRoll)
+		}
+	}
+
+	return diceRolls, true
+}
+
+// Recursive descent parser of rollTokens.
+//
+//	expression = term {("+" | "-") term}
+//	term       = unary {("*" | "/") unary}
+//	unary      = ("+" | "-") unary | primary
+//	primary    = "(" expression ")" | dice | number
+type rollArgParser struct {
+	tokens    []rollToken
+	position  int        // Index of the next token
+	diceRolls []DiceRoll // DiceRolls of the parsed dice tokens
+}
+
+// Parses every token into a formulaNode tree. Returns an error if tokens are left or invalid.
+func (parser *rollArgParser) parse() (*formulaNode, error) {
+	root, argErr := parser.parseExpression()
+	if argErr != nil {
+		return nil, argErr
+	}
+	if token := parser.peek(); token != nil {
+		return nil, newParseError(token.offset, fmt.Errorf("unexpected %s", token.text))
+	}
+	return root, nil
+}
+
+// Returns the next token without consuming it, nil at the end.
+func (parser *rollArgParser) peek() *rollToken {
+	if parser.position >= len(parser.tokens) {
+		return nil
+	}
+	return &parser.tokens[parser.position]
+}
+
+// Consumes the next token if it is one of kinds. Returns nil if it isn't.
+func (parser *rollArgParser) accept(kinds ...tokenKind) *rollToken {
+	token := parser.peek()
+	if token != nil && slices.Contains(kinds, token.kind) {
+		parser.position++
+		return token
+	}
+	return nil
+}
+
+func (parser *rollArgParser) parseExpression() (*formulaNode, error) {
+	node, argErr := parser.parseTerm()
+	for argErr == nil {
+		operator := parser.accept(plusToken, minusToken)
+		if operator == nil {
+			break
+		}
+		var right *formulaNode
+		if right, argErr = parser.parseTerm(); argErr == nil {
+			node = &formulaNode{operator.kind, node, right, 0}
+		}
+	}
+	return node, argErr
+}
+
+func (parser *rollArgParser) parseTerm() (*formulaNode, error) {
+	node, argErr := parser.parseUnary()
+	for argErr == nil {
+		operator := parser.accept(timesToken, divideToken)
+		if operator == nil {
+			break
+		}
+		var right *formulaNode
+		if right, argErr = parser.parseUnary(); argErr == nil {
+			node = &formulaNode{operator.kind, node, right, 0}
+		}
+	}
+	return node, argErr
+}
+
+func (parser *rollArgParser) parseUnary() (*formulaNode, error) {
+	if operator := parser.accept(plusToken, minusToken); operator != nil {
+		operand, argErr := parser.parseUnary()
+		if argErr != nil || operator.kind == plusToken {
+			return operand, argErr
+		}
+		return &formulaNode{minusToken, nil, operand, 0}, nil
+	}
+	return parser.parsePrimary()
+}
+
+func (parser *rollArgParser) parsePrimary() (*formulaNode, error) {
+	token := parser.accept(openToken, diceToken, numberToken)
+	if token == nil {
+		if next := parser.peek(); next != nil {
+			return nil, newParseError(next.offset, fmt.Errorf("unexpected %s", next.text))
+		}
+		return nil, newParseError(parser.endOffset(), fmt.Errorf("unexpected end"))
+	}
+
+	switch token.kind {
+	case openToken:
+		node, argErr := parser.parseExpression()
+		if argErr != nil {
+			return nil, argErr
+		}
+		if parser.accept(closeToken) == nil {
+			return nil, newParseError(token.offset, fmt.Errorf("missing ) for ("))
+		}
+		return node, nil
+	case diceToken:
+		diceRoll, argErr := parseDiceToken(*token)
+		if argErr != nil {
+			return nil, newParseError(token.offset, argErr)
+		}
+		if diceErr := validateDiceRoll(*diceRoll); diceErr != nil {
+			return nil, newParseError(token.offset, diceErr)
+		}
+		parser.diceRolls = append(parser.diceRolls, *diceRoll)
+		return &formulaNode{diceToken, nil, nil, len(parser.diceRolls) - 1}, nil
+	}
+
+	value, argErr := parseRollArgSlice(token.text)
+	if argErr != nil {
+		return nil, newParseError(token.offset, argErr)
+	}
+	return &formulaNode{numberToken, nil, nil, value}, nil
+}
+
+// Returns the offset following the last token.
+func (parser *rollArgParser) endOffset() int {
+	if len(parser.tokens) == 0 {
+		return 0
+	}
+	last := parser.tokens[len(parser.tokens)-1]
+	return last.offset + len(last.text)
 }
 
 // Parses a rollArg slice. Returns its value if valid, zero and an error if invalid.
@@ -2,6 +2,8 @@ package diceroller
 
 import (
 	"math/rand"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -13,7 +15,8 @@ var validRollArgs = []string{
 	"10000d10000-10000",
 	"1D8-00",
 	"1d100+0",
-	"20d12-9901"}
+	"20d12-9901",
+	"1+8d8+1"}
 
 // Valid Roll Args attribs
 var validRollArgsAttribs = []string{
@@ -40,7 +43,8 @@ var invalidRollArgs = []string{
 	"0d2",
 	"1d0",
 	"1b8",
-	"1+8d8+1"}
+	"(1d8",
+	"1d6+*2"}
 
 // Invalid Roll Args attribs
 var invalidRollArgsAttribs = []string{
@@ -96,8 +100,20 @@ func invalidArgParsingError(diceRollStr string, t *testing.T) {
 
 func FuzzParseRollArgs(f *testing.F) {
 	f.Add("8d4-1")
+	f.Add("adv 4d6kh3+2")
+	f.Add("droplow drophigh -6d20r1!cs>=19 fire")
 	f.Fuzz(func(t *testing.T, fuzzedRollArg string) {
-		parseRollArgs(fuzzedRollArg)
+		rollExprs, _ := parseRollArgs(strings.Fields(fuzzedRollArg)...)
+
+		// Every parsed DiceRoll parses back from its String
+		for e := range rollExprs {
+			for i := range rollExprs[e].diceRolls {
+				diceRoll := rollExprs[e].diceRolls[i]
+				if reparsed, argErr := ParseDiceRoll(diceRoll.String()); argErr != nil || !reflect.DeepEqual(*reparsed, diceRoll) {
+					t.Fatalf("DiceRoll %s parsed back to %v, %v", diceRoll, reparsed, argErr)
+				}
+			}
+		}
 	})
 }
 
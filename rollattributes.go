@@ -1,20 +1,25 @@
 package diceroller
 
-type rollAttribute int
+import (
+	"slices"
 
-// rollAttribute values. 0 is invalid.
+	"golang.org/x/exp/maps"
+)
+
+// A RollAttribute changes how DiceRolls are rolled, such as advantage, or groups them, such as hit.
+type RollAttribute int
+
+// RollAttribute values. 0 is invalid.
 const (
-	rollAttrib         rollAttribute = iota + 1
-	hitAttrib          rollAttribute = iota + 1
-	dmgAttrib          rollAttribute = iota + 1
-	critAttrib         rollAttribute = iota + 1
-	spellAttrib        rollAttribute = iota + 1
-	halfAttrib         rollAttribute = iota + 1
-	advantageAttrib    rollAttribute = iota + 1
-	disadvantageAttrib rollAttribute = iota + 1
-	dropHighAttrib     rollAttribute = iota + 1
-	dropLowAttrib      rollAttribute = iota + 1
-	minusAttrib        rollAttribute = iota + 1
+	RollAttrib         RollAttribute = iota + 1 // Separator, starts a plain rolling expression
+	HitAttrib          RollAttribute = iota + 1 // Separator, starts an attack rolling expression
+	DmgAttrib          RollAttribute = iota + 1 // Separator, starts a damage rolling expression
+	CritAttrib         RollAttribute = iota + 1 // Critical, applies the Roller critical damage policy
+	SpellAttrib        RollAttribute = iota + 1 // Spell, results also print the sum halved for saves
+	HalfAttrib         RollAttribute = iota + 1 // Halves the sum
+	AdvantageAttrib    RollAttribute = iota + 1 // Rolls each die twice and drops the lowest
+	DisadvantageAttrib RollAttribute = iota + 1 // Rolls each die twice and drops the highest
+	MinusAttrib        RollAttribute = iota + 1 // Subtracts the DiceRoll from the total
 )
 
 // Allowed rollAttribute string as RollArg.
@@ -29,41 +34,43 @@ const (
 	advantageLongStr    string = "advantage"
 	disadvantageStr     string = "dis"
 	disadvantageLongStr string = "disadvantage"
-	dropHighStr         string = "drophigh"
-	dropLowStr          string = "droplow"
 	minusAttribStr      string = "minus"
 )
 
-var rollAttributeMap = map[string]rollAttribute{
-	rollStr:             rollAttrib,
-	hitStr:              hitAttrib,
-	dmgStr:              dmgAttrib,
-	critStr:             critAttrib,
-	spellStr:            spellAttrib,
-	halfStr:             halfAttrib,
-	advantageStr:        advantageAttrib,
-	advantageLongStr:    advantageAttrib,
-	disadvantageStr:     disadvantageAttrib,
-	disadvantageLongStr: disadvantageAttrib,
-	dropHighStr:         dropHighAttrib,
-	dropLowStr:          dropLowAttrib,
-	minusAttribStr:      minusAttrib,
+var rollAttributeMap = map[string]RollAttribute{
+	rollStr:             RollAttrib,
+	hitStr:              HitAttrib,
+	dmgStr:              DmgAttrib,
+	critStr:             CritAttrib,
+	spellStr:            SpellAttrib,
+	halfStr:             HalfAttrib,
+	advantageStr:        AdvantageAttrib,
+	advantageLongStr:    AdvantageAttrib,
+	disadvantageStr:     DisadvantageAttrib,
+	disadvantageLongStr: DisadvantageAttrib,
+	minusAttribStr:      MinusAttrib,
 }
 
 type rollAttributes struct {
-	attribs map[rollAttribute]bool
+	attribs    map[RollAttribute]bool
+	explode    *explodeRule   // Exploding dice rule, nil when dice don't explode
+	reroll     *rerollRule    // Reroll rule, nil when dice aren't rerolled
+	keepDrop   []keepDropRule // Keep and drop rules, applied in order
+	success    *successRule   // Success counting rule, nil when dice are summed
+	crits      *critRange     // Critical range, nil to use the Roller range
+	damageType DamageType     // Type of the damage dealt, untyped by default
 }
 
 // Constructor for rollAttributes.
-func newRollAttributes(rollAttribs ...rollAttribute) *rollAttributes {
+func newRollAttributes(rollAttribs ...RollAttribute) *rollAttributes {
 	newRollAttributes := new(rollAttributes)
-	newRollAttributes.attribs = make(map[rollAttribute]bool)
+	newRollAttributes.attribs = make(map[RollAttribute]bool)
 	newRollAttributes.setRollAttrib(rollAttribs...)
 	return newRollAttributes
 }
 
 // To retrieve the roleAttribute string matching wanted roleAttribute.
-func rollAttributeMapKey(attribMap map[string]rollAttribute, wanted rollAttribute) string {
+func rollAttributeMapKey[T comparable](attribMap map[string]T, wanted T) string {
 	foundAttribStr := ""
 	for attribStr, attrib := range attribMap {
 		if attrib == wanted {
@@ -74,21 +81,51 @@ func rollAttributeMapKey(attribMap map[string]rollAttribute, wanted rollAttribut
 	return foundAttribStr
 }
 
-// Sets attrib to true and prevents rollAttribute incompatibilities.
-func (dndAttribs *rollAttributes) setRollAttrib(rollAttribs ...rollAttribute) {
+// Returns the canonical string of a rollAttribute, the shortest of its RollArg strings.
+func rollAttributeStr(rollAttrib RollAttribute) string {
+	attributeRegistry.RLock()
+	defer attributeRegistry.RUnlock()
+
+	attribStr := ""
+	for str, attrib := range rollAttributeMap {
+		if attrib == rollAttrib && (len(attribStr) == 0 || len(str) < len(attribStr) || len(str) == len(attribStr) && str < attribStr) {
+			attribStr = str
+		}
+	}
+	return attribStr
+}
+
+// RollAttribute string, such as "adv".
+func (rollAttrib RollAttribute) String() string {
+	return rollAttributeStr(rollAttrib)
+}
+
+// Sets attrib to true and prevents rollAttribute incompatibilities, such as advantage and disadvantage.
+func (dndAttribs *rollAttributes) setRollAttrib(rollAttribs ...RollAttribute) {
 	for i := range rollAttribs {
-		switch rollAttribs[i] {
-		case advantageAttrib:
-			delete(dndAttribs.attribs, disadvantageAttrib)
-		case disadvantageAttrib:
-			delete(dndAttribs.attribs, advantageAttrib)
+		for _, excluded := range rollAttributeExcludes(rollAttribs[i]) {
+			delete(dndAttribs.attribs, excluded)
 		}
 		dndAttribs.attribs[rollAttribs[i]] = true
 	}
 }
 
+// Sets the rollAttributes and appends the keepDropRules of other.
+func (dndAttribs *rollAttributes) mergeRollAttribs(other *rollAttributes) {
+	dndAttribs.setRollAttrib(maps.Keys(other.attribs)...)
+	dndAttribs.keepDrop = append(dndAttribs.keepDrop, other.keepDrop...)
+}
+
+// Returns a copy of the rollAttributes that can be changed without affecting the original.
+func (dndAttribs *rollAttributes) clone() *rollAttributes {
+	attribs := *dndAttribs
+	attribs.attribs = maps.Clone(dndAttribs.attribs)
+	attribs.keepDrop = slices.Clone(dndAttribs.keepDrop)
+	return &attribs
+}
+
 // Returns true if wanted is set.
-func (dndAttrib *rollAttributes) hasAttrib(wanted rollAttribute) bool {
+func (dndAttrib *rollAttributes) hasAttrib(wanted RollAttribute) bool {
 	found := false
 	if dndAttrib != nil {
 		found = dndAttrib.attribs[wanted]
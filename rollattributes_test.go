@@ -40,12 +40,12 @@ func TestPerformRollWithRollAttributes(t *testing.T) {
 func checkForAttribCompatibility(rollAttribs *rollAttributes, t *testing.T) {
 	for rollAttrib := range rollAttribs.attribs {
 		switch rollAttrib {
-		case advantageAttrib:
-			if rollAttribs.hasAttrib(disadvantageAttrib) {
+		case AdvantageAttrib:
+			if rollAttribs.hasAttrib(DisadvantageAttrib) {
 				t.Fatalf("Advantage attrib compatibility check failed, %s is set", rollAttributeMapKey(rollAttributeMap, rollAttrib))
 			}
-		case disadvantageAttrib:
-			if rollAttribs.hasAttrib(advantageAttrib) {
+		case DisadvantageAttrib:
+			if rollAttribs.hasAttrib(AdvantageAttrib) {
 				t.Fatalf("Disadvantage attrib compatibility check failed, %s is set", rollAttributeMapKey(rollAttributeMap, rollAttrib))
 			}
 		}
@@ -56,7 +56,7 @@ func FuzzSetRollAttrib(f *testing.F) {
 	f.Add(0)
 	f.Fuzz(func(t *testing.T, fuzzedRollAttrib int) {
 		rollAttribs := newRollAttributes()
-		rollAttrib := rollAttribute(fuzzedRollAttrib)
+		rollAttrib := RollAttribute(fuzzedRollAttrib)
 		rollAttribs.setRollAttrib(rollAttrib)
 
 		if !rollAttribs.hasAttrib(rollAttrib) {
@@ -0,0 +1,179 @@
+package diceroller
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"math/rand/v2"
+)
+
+// A Roller performs RollArgs and DiceRolls, drawing every die from its own random source.
+// Give each game table its own Roller to isolate their random state.
+type Roller struct {
+	rand       *rand.Rand     // Random generator wrapping the Roller source
+	seeded     *seededSource  // Seeded source recording stream positions, nil when not seeded
+	crits      critRange      // Natural d20 rolls scoring critical hits and fails
+	critDamage critDamageRule // How critical DiceRolls increase their result
+	mode       EvaluationMode // How partially invalid input is handled
+	pipeline   RollPipeline   // RollStages performing each DiceRoll, nil for the default RollPipeline
+}
+
+// Default Roller used by the package level functions, backed by the package-global generator.
+var defaultRoller = NewRoller(nil)
+
+// Roller constructor using source as random source. A nil source uses the package-global generator.
+// Sources from math/rand/v2, such as PCG and ChaCha8, are not safe for concurrent use.
+func NewRoller(source rand.Source) *Roller {
+	if source == nil {
+		source = globalSource{}
+	}
+	return &Roller{rand.New(source), nil, defaultCritRange, defaultCritDamage, LenientMode, nil}
+}
+
+// Seeded Roller constructor. Each RollResult records the seed and stream position
+// it was rolled from, which ReplayRollArgs uses to reproduce the exact same dice.
+// Seeded Rollers are not safe for concurrent use.
+func NewSeededRoller(seed uint64) *Roller {
+	source := newSeededSource(seed)
+	return &Roller{rand.New(source), source, defaultCritRange, defaultCritDamage, LenientMode, nil}
+}
+
+// Roller constructor using a math/rand/v2 PCG source seeded with seed1 and seed2.
+func NewPCGRoller(seed1 uint64, seed2 uint64) *Roller {
+	return NewRoller(rand.NewPCG(seed1, seed2))
+}
+
+// Roller constructor using a math/rand/v2 ChaCha8 source seeded with seed.
+func NewChaCha8Roller(seed [32]byte) *Roller {
+	return NewRoller(rand.NewChaCha8(seed))
+}
+
+// Roller constructor using crypto/rand as random source.
+func NewCryptoRoller() *Roller {
+	return NewRoller(cryptoSource{})
+}
+
+// Straightforward rolling using RollArgs. Returns the sum, invalid RollArgs are worth 0.
+// In strict mode, returns 0 if anything is invalid.
+func (roller *Roller) PerformRollArgsAndSum(rollArgs ...string) int {
+	sum, _ := roller.SumRollArgs(rollArgs...)
+	return sum
+}
+
+// Performs an array of RollArgs. Returns a RollResult array for valid RollArgs and an error array for invalid ones.
+// In strict mode, returns no RollResult and every error if anything is invalid, rolling no dice unless every RollArg is valid.
+func (roller *Roller) PerformRollArgs(rollArgs ...string) ([]RollResult, []error) {
+	rollExprs, argErrs := parseRollArgs(rollArgs...)
+	if roller.strict() && len(argErrs) > 0 {
+		return nil, argErrs
+	}
+	return roller.performRollingExpressionsInMode(argErrs, rollExprs...)
+}
+
+// Performs an array of DiceRoll. Returns the sum, invalid DiceRolls are worth 0.
+// In strict mode, returns 0 if anything is invalid.
+func (roller *Roller) PerformDiceRollsAndSum(diceRolls ...DiceRoll) int {
+	sum, _ := roller.SumDiceRolls(diceRolls...)
+	return sum
+}
+
+// Performs an array of DiceRoll. Returns a RollResult array for valid DiceRolls and an error array for invalid ones.
+// In strict mode, returns no RollResult and every error if anything is invalid, rolling no dice unless every DiceRoll is valid.
+func (roller *Roller) PerformDiceRolls(diceRolls ...DiceRoll) (results []RollResult, diceErrs []error) {
+	return roller.performRollingExpressionsInMode(nil, *newRollingExpression(diceRolls...))
+}
+
+// Performs rollExprs following the Roller EvaluationMode, reporting argErrs first. Strict mode validates every
+// DiceRoll before rolling, and discards the results of errors only found while rolling, such as division by zero.
+func (roller *Roller) performRollingExpressionsInMode(argErrs []error, rollExprs ...rollingExpression) ([]RollResult, []error) {
+	if roller.strict() {
+		if diceErrs := validateRollingExpressions(rollExprs...); len(diceErrs) > 0 {
+			return nil, append(argErrs, diceErrs...)
+		}
+	}
+	results, diceErrs := roller.performRollingExpressions(rollExprs...)
+	if roller.strict() && len(diceErrs) > 0 {
+		return nil, diceErrs
+	}
+	return results, append(argErrs, diceErrs...)
+}
+
+// Performs diceRoll. Returns the sum if valid, zero if invalid.
+func (roller *Roller) Roll(diceRoll DiceRoll) int {
+	sum := 0
+	if result, diceErr := roller.validateAndperformRoll(diceRoll); diceErr == nil {
+		sum = result.sum
+	}
+	return sum
+}
+
+// Replays RollArgs from a RollSeed recorded by a seeded Roller with default settings. Replaying the RollSeed of
+// the first RollResult with the original RollArgs reproduces every RollResult exactly.
+func ReplayRollArgs(rollSeed RollSeed, rollArgs ...string) ([]RollResult, []error) {
+	return defaultRoller.ReplayRollArgs(rollSeed, rollArgs...)
+}
+
+// Replays RollArgs from a RollSeed, with the critical range, critical damage policy, evaluation mode and
+// RollPipeline of the Roller. Replaying the RollSeed of the first RollResult with the original RollArgs
+// on the seeded Roller which rolled them reproduces every RollResult exactly. The Roller source is left untouched.
+func (roller *Roller) ReplayRollArgs(rollSeed RollSeed, rollArgs ...string) ([]RollResult, []error) {
+	source := newSeededSource(rollSeed.Seed)
+	source.skip(rollSeed.Position)
+	replayRoller := *roller
+	replayRoller.rand, replayRoller.seeded = rand.New(source), source
+	return replayRoller.PerformRollArgs(rollArgs...)
+}
+
+// Records the seed and stream position a RollResult was rolled from.
+type RollSeed struct {
+	Seed     uint64 `json:"seed" yaml:"seed"`         // Seed of the Roller source
+	Position uint64 `json:"position" yaml:"position"` // Ammount of values drawn from the source before rolling
+}
+
+// Returns the current RollSeed of a seeded Roller. Returns false if the Roller is not seeded.
+func (roller *Roller) rollSeed() (RollSeed, bool) {
+	if roller.seeded == nil {
+		return RollSeed{}, false
+	}
+	return RollSeed{roller.seeded.seed, roller.seeded.position}, true
+}
+
+// Seeded PCG source counting every value drawn from it.
+type seededSource struct {
+	pcg      *rand.PCG
+	seed     uint64
+	position uint64
+}
+
+// Constructor of seededSource.
+func newSeededSource(seed uint64) *seededSource {
+	return &seededSource{rand.NewPCG(seed, seed), seed, 0}
+}
+
+func (source *seededSource) Uint64() uint64 {
+	source.position++
+	return source.pcg.Uint64()
+}
+
+// Advances the source to position by drawing and discarding values.
+func (source *seededSource) skip(position uint64) {
+	for source.position < position {
+		source.Uint64()
+	}
+}
+
+// Random source drawing from the package-global generator, safe for concurrent use.
+type globalSource struct{}
+
+func (globalSource) Uint64() uint64 {
+	return rand.Uint64()
+}
+
+// Random source drawing from crypto/rand, safe for concurrent use.
+type cryptoSource struct{}
+
+func (cryptoSource) Uint64() uint64 {
+	var buf [8]byte
+	// crypto/rand.Read never returns an error on supported platforms
+	crand.Read(buf[:])
+	return binary.LittleEndian.Uint64(buf[:])
+}
@@ -0,0 +1,175 @@
+package diceroller
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// Random source always returning the max value, every die rolls its highest face.
+type maxSource struct{}
+
+func (maxSource) Uint64() uint64 {
+	return math.MaxUint64
+}
+
+// Random source always returning a tiny value, every die rolls its lowest face.
+type minSource struct{}
+
+func (minSource) Uint64() uint64 {
+	return 1 << 40
+}
+
+func TestRollerWithCustomSource(t *testing.T) {
+	roller := NewRoller(maxSource{})
+
+	results, diceErrs := roller.PerformRollArgs("4d6+2", "adv", "1d20")
+	if diceErrs != nil {
+		t.Fatalf("Unexpected dice roll errors: %s", diceErrs)
+	}
+
+	if sum := results[0].Sum(); sum != 26 {
+		t.Fatalf("4d6+2 with max source result = %d, wanted 26", sum)
+	}
+
+	if dice := results[1].results[0].dice; len(dice) != 1 || dice[0] != 20 {
+		t.Fatalf("adv 1d20 with max source rolled %v, wanted [20]", dice)
+	}
+}
+
+func TestPCGRollerIsReproducible(t *testing.T) {
+	rollArgs := []string{"adv", "drophigh", "10d20+3", "dmg", "8d6"}
+	roller1, roller2 := NewPCGRoller(4, 20), NewPCGRoller(4, 20)
+
+	for i := 0; i < 10; i++ {
+		results1, _ := roller1.PerformRollArgs(rollArgs...)
+		results2, _ := roller2.PerformRollArgs(rollArgs...)
+
+		for e := range results1 {
+			for i := range results1[e].results {
+				result1, result2 := results1[e].results[i], results2[e].results[i]
+				if !sameDiceRollResult(result1, result2) {
+					t.Fatalf("PCG Rollers with same seeds rolled %s and %s", result1, result2)
+				}
+			}
+		}
+	}
+}
+
+func TestChaCha8RollerIsReproducible(t *testing.T) {
+	seed := [32]byte{1, 2, 3}
+	diceRoll := *newDiceRoll(20, 100, 5)
+
+	if sum1, sum2 := NewChaCha8Roller(seed).Roll(diceRoll), NewChaCha8Roller(seed).Roll(diceRoll); sum1 != sum2 {
+		t.Fatalf("ChaCha8 Rollers with same seed rolled %d and %d", sum1, sum2)
+	}
+}
+
+func TestCryptoRoller(t *testing.T) {
+	roller := NewCryptoRoller()
+	for i := range validDiceRollsValues {
+		result, diceErr := roller.validateAndperformRoll(validDiceRollsValues[i].diceRoll)
+		if diceErr != nil {
+			t.Fatalf("Unexpected dice roll error: %s", diceErr.Error())
+		}
+		validateDiceRollResult(*result, validDiceRollsValues[i], t)
+	}
+}
+
+func TestRollerRollInvalidDiceRoll(t *testing.T) {
+	for i := range invalidDiceRollsValues {
+		if sum := NewRoller(nil).Roll(invalidDiceRollsValues[i].diceRoll); sum != 0 {
+			t.Fatalf("Invalid DiceRoll %s rolled %d, wanted 0", invalidDiceRollsValues[i].wantedDiceStr, sum)
+		}
+	}
+}
+
+func TestSeededRollerReplay(t *testing.T) {
+	rollArgs := []string{"adv", "droplow", "drophigh", "6d20+3", "dis", "4d6", "crit", "2d8-1"}
+	roller := NewSeededRoller(1234)
+
+	// Roll a few times to move the stream position forward
+	for i := 0; i < 5; i++ {
+		results, _ := roller.PerformRollArgs(rollArgs...)
+
+		rollSeed, seeded := results[0].Seed()
+		if !seeded {
+			t.Fatalf("Seeded Roller result has no RollSeed")
+		}
+
+		replayed, errs := ReplayRollArgs(rollSeed, rollArgs...)
+		if errs != nil {
+			t.Fatalf("Replay returned errors: %s", errs)
+		}
+
+		for e := range results {
+			for d := range results[e].results {
+				if !sameDiceRollResult(results[e].results[d], replayed[e].results[d]) {
+					t.Fatalf("Replayed %s, wanted %s", replayed[e].results[d], results[e].results[d])
+				}
+			}
+		}
+	}
+}
+
+func TestSeededRollerReplaySingleResult(t *testing.T) {
+	results, _ := NewSeededRoller(42).PerformRollArgs("roll", "3d6", "adv", "droplow", "4d6")
+
+	rollSeed, _ := results[1].Seed()
+	replayed, _ := ReplayRollArgs(rollSeed, "adv", "droplow", "4d6")
+
+	if !sameDiceRollResult(results[1].results[0], replayed[0].results[0]) {
+		t.Fatalf("Replayed %s, wanted %s", replayed[0].results[0], results[1].results[0])
+	}
+}
+
+func TestSeededRollerReplayWithSettings(t *testing.T) {
+	rollArgs := []string{"crit", "2d6+2", "1d20cs>=15"}
+	roller := NewSeededRoller(7)
+	roller.SetCritDamagePolicy(CritMaxPlusRoll, 1)
+	roller.SetCritRange(18, 2)
+	roller.SetEvaluationMode(StrictMode)
+	pipeline, _ := roller.RollPipeline().InsertAfter(ModifierStage, RollStage{"bless", func(state *RollState) {
+		state.SetSum(state.Sum() + state.RollDie(4))
+	}})
+	roller.SetRollPipeline(pipeline)
+
+	for i := 0; i < 5; i++ {
+		results, _ := roller.PerformRollArgs(rollArgs...)
+		rollSeed, _ := results[0].Seed()
+		position := roller.seeded.position
+
+		replayed, errs := roller.ReplayRollArgs(rollSeed, rollArgs...)
+		if errs != nil {
+			t.Fatalf("Replay returned errors: %s", errs)
+		}
+		if roller.seeded.position != position {
+			t.Fatalf("Replay moved the Roller source from position %d to %d", position, roller.seeded.position)
+		}
+		for d := range results[0].results {
+			if !sameDiceRollResult(results[0].results[d], replayed[0].results[d]) {
+				t.Fatalf("Replayed %s, wanted %s", replayed[0].results[d], results[0].results[d])
+			}
+		}
+	}
+}
+
+func TestUnseededRollerHasNoSeed(t *testing.T) {
+	results, _ := PerformRollArgs("1d6")
+	if _, seeded := results[0].Seed(); seeded {
+		t.Fatalf("Unseeded Roller result has a RollSeed")
+	}
+}
+
+// Returns true if both diceRollResults rolled the same kept and dropped dice.
+func sameDiceRollResult(result1 DiceRollResult, result2 DiceRollResult) bool {
+	return fmt.Sprint(result1.dice, result1.advDisDropped, result1.highDropped, result1.lowDropped, result1.sum) ==
+		fmt.Sprint(result2.dice, result2.advDisDropped, result2.highDropped, result2.lowDropped, result2.sum)
+}
+
+func FuzzPCGRollerPerformRollArgs(f *testing.F) {
+	f.Add(uint64(1), uint64(2), "2d8+1")
+	f.Fuzz(func(t *testing.T, seed1 uint64, seed2 uint64, fuzzedRollArg string) {
+		NewPCGRoller(seed1, seed2).PerformRollArgs(fuzzedRollArg)
+	})
+}
@@ -0,0 +1,132 @@
+package diceroller
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/exp/maps"
+)
+
+// A ValidationErrorKind tells why a DiceRoll is invalid. Kinds are errors matching ValidationErrors with errors.Is.
+type ValidationErrorKind int
+
+// ValidationErrorKind values. 0 is invalid.
+const (
+	TooManyDice  ValidationErrorKind = iota + 1 // Dice ammount above the max
+	NoDice       ValidationErrorKind = iota + 1 // Dice ammount below 1
+	BadSize      ValidationErrorKind = iota + 1 // Dice size below 2 or above the max
+	BadModifier  ValidationErrorKind = iota + 1 // Modifier above the max or below its negative
+	BadKeepDrop  ValidationErrorKind = iota + 1 // Keep or drop rule keeping or dropping no dice
+	BadReroll    ValidationErrorKind = iota + 1 // Reroll rule rerolling every face
+	BadExplode   ValidationErrorKind = iota + 1 // Explode rule exploding on every face
+	BadSuccess   ValidationErrorKind = iota + 1 // Success counting ambiguous with exploding dice
+	BadCritRange ValidationErrorKind = iota + 1 // Critical range on a die other than a d20, or matching no face
+	BadAttribute ValidationErrorKind = iota + 1 // Custom rollAttribute hooks changing the dice to invalid values
+)
+
+var validationErrorKindStrs = map[ValidationErrorKind]string{
+	TooManyDice:  "too many dice",
+	NoDice:       "no dice",
+	BadSize:      "bad dice size",
+	BadModifier:  "bad modifier",
+	BadKeepDrop:  "bad keep or drop",
+	BadReroll:    "bad reroll",
+	BadExplode:   "bad explode",
+	BadSuccess:   "bad success counting",
+	BadCritRange: "bad critical range",
+	BadAttribute: "bad custom attribute",
+}
+
+// Max edit distance between a misspelled RollArg and its suggestion.
+const maxSuggestionDistance int = 2
+
+// A ValidationError reports an invalid DiceRoll.
+type ValidationError struct {
+	Kind     ValidationErrorKind // Why the DiceRoll is invalid
+	DiceRoll string              // Invalid DiceRoll string
+	Err      error               // Detailed reason
+}
+
+// A ParseError reports an invalid RollArg.
+type ParseError struct {
+	Arg        int    // Index of the RollArg in the parsed RollArgs
+	RollArg    string // Invalid RollArg
+	Offset     int    // Character offset of the error in the RollArg
+	Suggestion string // Closest known RollArg for misspelled rollAttributes, empty if none
+	Err        error  // Detailed reason, a ValidationError for invalid DiceRolls
+}
+
+// Human readable ValidationErrorKind string.
+func (kind ValidationErrorKind) Error() string {
+	return validationErrorKindStrs[kind]
+}
+
+// Human readable ValidationError string.
+func (validationErr *ValidationError) Error() string {
+	return fmt.Sprintf("invalid DiceRoll %s: %s", validationErr.DiceRoll, validationErr.Err.Error())
+}
+
+// Returns the ValidationErrorKind and the detailed reason, for errors.Is and errors.As.
+func (validationErr *ValidationError) Unwrap() []error {
+	return []error{validationErr.Kind, validationErr.Err}
+}
+
+// Human readable ParseError string.
+func (parseErr *ParseError) Error() string {
+	parseErrStr := fmt.Sprintf("invalid RollArg %d %q at offset %d: %s", parseErr.Arg, parseErr.RollArg, parseErr.Offset, parseErr.Err.Error())
+	if len(parseErr.Suggestion) > 0 {
+		parseErrStr += fmt.Sprintf(", did you mean %q?", parseErr.Suggestion)
+	}
+	return parseErrStr
+}
+
+// Returns the detailed reason, for errors.Is and errors.As.
+func (parseErr *ParseError) Unwrap() error {
+	return parseErr.Err
+}
+
+// Returns a ParseError at offset, for a RollArg set by the caller.
+func newParseError(offset int, err error) *ParseError {
+	return &ParseError{0, "", offset, "", err}
+}
+
+// Returns the known attribute RollArg closest to a misspelled rollArg. Returns empty if rollArg is known or none is close enough.
+func suggestRollArg(rollArg string) string {
+	candidates := append(rollAttributeKeywords(), maps.Keys(keepDropAliasMap)...)
+	candidates = append(candidates, maps.Keys(damageTypeMap)...)
+	slices.Sort(candidates)
+
+	if slices.Contains(candidates, rollArg) {
+		return ""
+	}
+
+	suggestion, bestDistance := "", maxSuggestionDistance+1
+	for _, candidate := range candidates {
+		if distance := editDistance(strings.ToLower(rollArg), candidate); distance < bestDistance && distance < len(candidate) {
+			suggestion, bestDistance = candidate, distance
+		}
+	}
+	return suggestion
+}
+
+// Returns the Levenshtein distance between a and b.
+func editDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			substitution := previous[j-1]
+			if a[i-1] != b[j-1] {
+				substitution++
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, substitution)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,89 @@
+package diceroller
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseErrors(t *testing.T) {
+	parseErrs := []struct {
+		rollArgs   []string
+		arg        int
+		offset     int
+		suggestion string
+	}{
+		{[]string{"1d6", "advantge", "1d20"}, 1, 0, "advantage"},
+		{[]string{"ADV", "1d20"}, 0, 0, "adv"},
+		{[]string{"dmg", "2d6", "fier"}, 2, 0, "fire"},
+		{[]string{"fire", "1d6"}, 0, 0, ""},
+		{[]string{"1d6", "2d8+x"}, 1, 4, ""},
+		{[]string{"(1d8+2"}, 0, 0, ""},
+		{[]string{"1d6+2*3d4+"}, 0, 10, ""},
+		{[]string{"1d4", "2d6+1d8rr1"}, 1, 7, ""},
+		{[]string{"zzzzzzzz"}, 0, 0, ""},
+	}
+
+	for i := range parseErrs {
+		_, argErrs := parseRollArgs(parseErrs[i].rollArgs...)
+		if len(argErrs) != 1 {
+			t.Fatalf("RollArgs %v returned %d errors, wanted 1", parseErrs[i].rollArgs, len(argErrs))
+		}
+		var parseErr *ParseError
+		if !errors.As(argErrs[0], &parseErr) {
+			t.Fatalf("RollArgs %v error %s is not a ParseError", parseErrs[i].rollArgs, argErrs[0])
+		}
+		if parseErr.Arg != parseErrs[i].arg || parseErr.Offset != parseErrs[i].offset || parseErr.Suggestion != parseErrs[i].suggestion {
+			t.Fatalf("RollArgs %v returned %s, wanted arg %d offset %d suggestion %q", parseErrs[i].rollArgs, parseErr,
+				parseErrs[i].arg, parseErrs[i].offset, parseErrs[i].suggestion)
+		}
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	validationErrs := []struct {
+		rollArg string
+		kind    ValidationErrorKind
+	}{
+		{"123456d6", TooManyDice},
+		{"0d6", NoDice},
+		{"1d1", BadSize},
+		{"1d6+123456", BadModifier},
+		{"4d6dh5", BadKeepDrop},
+		{"4d6kh10", BadKeepDrop},
+		{"2d6r<=6", BadReroll},
+		{"2d6!<=6", BadExplode},
+		{"1d8cs>=7", BadCritRange},
+	}
+
+	for i := range validationErrs {
+		_, argErrs := PerformRollArgs("1d4", validationErrs[i].rollArg)
+		if len(argErrs) != 1 || !errors.Is(argErrs[0], validationErrs[i].kind) {
+			t.Fatalf("RollArg %s returned %v, wanted %s", validationErrs[i].rollArg, argErrs, validationErrs[i].kind)
+		}
+		var validationErr *ValidationError
+		var parseErr *ParseError
+		if !errors.As(argErrs[0], &validationErr) || !errors.As(argErrs[0], &parseErr) || parseErr.Arg != 1 {
+			t.Fatalf("RollArg %s error %s is not an argument 1 ValidationError", validationErrs[i].rollArg, argErrs[0])
+		}
+	}
+
+	if _, diceErr := D(6).Times(0).Build(); !errors.Is(diceErr, NoDice) || errors.Is(diceErr, TooManyDice) {
+		t.Fatalf("Built DiceRoll without dice returned %s, wanted %s", diceErr, NoDice)
+	}
+}
+
+func TestWrappedValidationErrors(t *testing.T) {
+	// Formulas, rolled formulas and saving throws keep the ValidationError
+	_, argErrs := PerformRollArgs("(1d6kh0)*2")
+	var validationErr *ValidationError
+	if len(argErrs) != 1 || !errors.As(argErrs[0], &validationErr) || validationErr.Kind != BadKeepDrop {
+		t.Fatalf("Formula (1d6kh0)*2 returned %v, wanted a %s ValidationError", argErrs, BadKeepDrop)
+	}
+	if _, argErrs = PerformRollArgs("shrink", "(1d3)*2"); len(argErrs) != 1 || !errors.Is(argErrs[0], BadAttribute) {
+		t.Fatalf("Shrunk formula (1d3)*2 returned %v, wanted %s", argErrs, BadAttribute)
+	}
+	_, saveErrs := ResolveSavingThrows(15, SaveHalf, []SaveTarget{{"Ogre", 123456, false}}, "8d6")
+	if len(saveErrs) != 1 || !errors.As(saveErrs[0], &validationErr) || validationErr.Kind != BadModifier {
+		t.Fatalf("Saving throw +123456 returned %v, wanted a %s ValidationError", saveErrs, BadModifier)
+	}
+}
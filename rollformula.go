@@ -0,0 +1,148 @@
+package diceroller
+
+import (
+	"fmt"
+	"slices"
+)
+
+// A formulaNode is a node of a RollArg arithmetic expression tree.
+type formulaNode struct {
+	kind  tokenKind    // Operator token kind, numberToken or diceToken for leaves
+	left  *formulaNode // Left operand, nil for leaves and unary minus
+	right *formulaNode // Right operand, nil for leaves
+	value int          // Constant value of numberToken leaves, DiceRoll index of diceToken leaves
+}
+
+// A rollFormula is a RollArg arithmetic expression that can't be reduced to a sum of DiceRolls, such as "(1d8+2)*2".
+type rollFormula struct {
+	root      *formulaNode
+	diceRolls []DiceRoll // DiceRolls of the formula diceToken leaves
+	rollArg   string     // Parsed RollArg
+}
+
+// Results of performing a rollFormula.
+type FormulaResult struct {
+	formula rollFormula
+	results []DiceRollResult // Results of the formula DiceRolls
+	sum     int              // Formula result
+}
+
+// Validates and performs the DiceRolls of formula and evaluates it. Returns a FormulaResult if valid, an error if invalid.
+func (roller *Roller) validateAndPerformFormula(formula rollFormula) (*FormulaResult, error) {
+	formulaResult := &FormulaResult{formula, make([]DiceRollResult, 0), 0}
+
+	for i := range formula.diceRolls {
+		result, diceErr := roller.validateAndperformRoll(formula.diceRolls[i])
+		if diceErr != nil {
+			return nil, fmt.Errorf("%s: %w", formula.rollArg, diceErr)
+		}
+		formulaResult.results = append(formulaResult.results, *result)
+	}
+
+	sum, evalErr := formula.root.evaluate(formulaResult.results)
+	if evalErr != nil {
+		return nil, fmt.Errorf("%s: %w", formula.rollArg, evalErr)
+	}
+	formulaResult.sum = sum
+
+	return formulaResult, nil
+}
+
+// Returns the formula RollArg.
+func (result FormulaResult) Formula() string {
+	return result.formula.rollArg
+}
+
+// Returns the results of the formula DiceRolls.
+func (result FormulaResult) Results() []DiceRollResult {
+	return slices.Clone(result.results)
+}
+
+// Returns the formula result.
+func (result FormulaResult) Sum() int {
+	return result.sum
+}
+
+// Evaluates the node using the DiceRoll results. Divisions round down. Returns an error when dividing by zero.
+func (node *formulaNode) evaluate(results []DiceRollResult) (int, error) {
+	switch node.kind {
+	case numberToken:
+		return node.value, nil
+	case diceToken:
+		return results[node.value].sum, nil
+	}
+
+	right, evalErr := node.right.evaluate(results)
+	if evalErr != nil {
+		return 0, evalErr
+	}
+
+	// Unary minus
+	if node.left == nil {
+		return -right, nil
+	}
+
+	left, evalErr := node.left.evaluate(results)
+	if evalErr != nil {
+		return 0, evalErr
+	}
+
+	value := 0
+	switch node.kind {
+	case plusToken:
+		value = left + right
+	case minusToken:
+		value = left - right
+	case timesToken:
+		value = left * right
+	case divideToken:
+		if right == 0 {
+			return 0, fmt.Errorf("division by zero")
+		}
+		value = floorDivide(left, right)
+	}
+
+	return value, nil
+}
+
+// Integer division rounding down, towards negative infinity.
+func floorDivide(dividend int, divisor int) int {
+	quotient := dividend / divisor
+	if dividend%divisor != 0 && (dividend < 0) != (divisor < 0) {
+		quotient--
+	}
+	return quotient
+}
+
+// Collects the terms of an additive node with their sign. Returns false if the node has other operators.
+func (node *formulaNode) additiveTerms(sign int, terms *[]signedFormulaNode) bool {
+	switch node.kind {
+	case numberToken, diceToken:
+		*terms = append(*terms, signedFormulaNode{sign, node})
+		return true
+	case plusToken, minusToken:
+		rightSign := sign
+		if node.kind == minusToken {
+			rightSign = -sign
+		}
+		// Unary minus has no left operand
+		return (node.left == nil || node.left.additiveTerms(sign, terms)) && node.right.additiveTerms(rightSign, terms)
+	}
+	return false
+}
+
+// A formulaNode leaf and its sign in an additive expression.
+type signedFormulaNode struct {
+	sign int
+	node *formulaNode
+}
+
+// Human readable FormulaResult string.
+func (result FormulaResult) String() string {
+	resultStr := fmt.Sprintf(" Result of formula \"%s\": \n", result.formula.rollArg)
+	for i := range result.results {
+		resultStr += result.results[i].String()
+	}
+	resultStr += fmt.Sprintf("  Formula:   %d\n", result.sum)
+	return resultStr
+}
@@ -0,0 +1,135 @@
+package diceroller
+
+import (
+	"testing"
+)
+
+// Valid arithmetic Roll Args
+var validFormulaRollArgs = []string{
+	"1d20+5+2",
+	"2d6+1d4+3",
+	"(1d8+2)*2",
+	"1d20-1d4",
+	"3-1d4",
+	"(2d6+1d4+3)/2",
+	"-(1d6)*-(1d6)",
+	"1d100 / 7 * 2",
+	"((((1d4))))"}
+
+// Invalid arithmetic Roll Args
+var invalidFormulaRollArgs = []string{
+	"5+3",
+	"(1d8+2",
+	"1d8+2)",
+	"1d8**2",
+	"()",
+	"1d8 1d8",
+	"1d6/0+1d4*",
+	"2d6+123456"}
+
+func TestParseValidFormulaRollArgs(t *testing.T) {
+	for i := range validFormulaRollArgs {
+		if _, _, argErr := parseRollArgExpression(validFormulaRollArgs[i]); argErr != nil {
+			validArgParsingError(argErr, t)
+		}
+	}
+}
+
+func TestParseInvalidFormulaRollArgs(t *testing.T) {
+	for i := range invalidFormulaRollArgs {
+		if _, _, argErr := parseRollArgExpression(invalidFormulaRollArgs[i]); argErr == nil {
+			invalidArgParsingError(invalidFormulaRollArgs[i], t)
+		}
+	}
+}
+
+func TestReduceAdditiveFormulas(t *testing.T) {
+	// DiceRoll strings of reduced RollArgs, none for rollFormulas
+	wantedDiceRolls := map[string][]string{
+		"1d20+5+2":   {"1d20+7"},
+		"2d6+1d4+3":  {"2d6+3", "1d4"},
+		"1d20-1d4":   {"1d20", "-1d4"},
+		"-1d4+1d6-2": {"-1d4", "1d6-2"},
+		"-5d6-1":     {"-5d6-1"},
+		"1+8d8+1":    {"8d8+2"},
+		"3-1d4":      nil,
+		"(1d8+2)*2":  nil,
+	}
+
+	for rollArg, wanted := range wantedDiceRolls {
+		diceRolls, formula, argErr := parseRollArgExpression(rollArg)
+		if argErr != nil {
+			validArgParsingError(argErr, t)
+		}
+		if (formula == nil) != (wanted != nil) || len(diceRolls) != len(wanted) {
+			t.Fatalf("RollArg %s parsed into %v and formula %v, wanted %v", rollArg, diceRolls, formula, wanted)
+		}
+		for i := range diceRolls {
+			if diceRolls[i].String() != wanted[i] {
+				t.Fatalf("RollArg %s parsed into %s, wanted %s", rollArg, diceRolls[i], wanted[i])
+			}
+		}
+	}
+}
+
+func TestEvaluateFormulas(t *testing.T) {
+	// Results with every die rolling its highest face
+	wantedSums := map[string]int{
+		"(1d8+2)*2":     20,
+		"1d20/3":        6,
+		"(1d6-9)/2":     -2,
+		"3-1d4":         -1,
+		"-(1d6)*-(1d6)": 36,
+		"2*(1d4+1d6)":   20,
+		"1d20+5+2":      27,
+	}
+
+	for rollArg, wantedSum := range wantedSums {
+		results, errs := NewRoller(maxSource{}).PerformRollArgs(rollArg)
+		if errs != nil {
+			t.Fatalf("RollArg %s returned errors: %s", rollArg, errs)
+		}
+		if sum := RollResultsSum(results...); sum != wantedSum {
+			t.Fatalf("RollArg %s with max source result = %d, wanted %d", rollArg, sum, wantedSum)
+		}
+	}
+}
+
+func TestEvaluateDivisionByZero(t *testing.T) {
+	results, errs := NewRoller(maxSource{}).PerformRollArgs("1d6/(1d6-6)")
+	if len(errs) != 1 {
+		t.Fatalf("Division by zero returned %d errors, wanted 1", len(errs))
+	}
+	if sum := RollResultsSum(results...); sum != 0 {
+		t.Fatalf("Division by zero result = %d, wanted 0", sum)
+	}
+}
+
+func TestFloorDivide(t *testing.T) {
+	wanted := [][3]int{{7, 2, 3}, {-7, 2, -4}, {7, -2, -4}, {-7, -2, 3}, {6, 3, 2}, {-6, 3, -2}, {0, 5, 0}}
+	for i := range wanted {
+		if quotient := floorDivide(wanted[i][0], wanted[i][1]); quotient != wanted[i][2] {
+			t.Fatalf("%d / %d = %d, wanted %d", wanted[i][0], wanted[i][1], quotient, wanted[i][2])
+		}
+	}
+}
+
+func TestFormulaWithRollAttributes(t *testing.T) {
+	results, errs := NewRoller(maxSource{}).PerformRollArgs("hit", "1d20+5", "dmg", "(1d8+2)*2")
+	if errs != nil {
+		t.Fatalf("RollArgs returned errors: %s", errs)
+	}
+
+	// Natural 20 crit doubles the formula dice
+	formulaResult := results[1].formulaResults[0]
+	if len(formulaResult.results[0].dice) != 2 || formulaResult.sum != 36 {
+		t.Fatalf("Critical formula result = %s, wanted 2 dice and 36", formulaResult)
+	}
+}
+
+func FuzzParseRollArgExpression(f *testing.F) {
+	f.Add("(1d8+2)*2")
+	f.Fuzz(func(t *testing.T, fuzzedRollArg string) {
+		parseRollArgExpression(fuzzedRollArg)
+	})
+}
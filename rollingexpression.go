@@ -1,11 +1,39 @@
 package diceroller
 
-// Represents a sequence of DiceRolls.
+type expressionKind int
+
+// expressionKind values. 0 is invalid.
+const (
+	plainExpression  expressionKind = iota + 1 // Neither attack nor damage, such as "roll" expressions
+	attackExpression expressionKind = iota + 1 // Attack roll started by "hit", owning the following damage expressions
+	damageExpression expressionKind = iota + 1 // Damage started by "dmg", critical when its attack scored a critical hit
+)
+
+// expressionKind strings, the RollArgs starting each kind of rollingExpression.
+var expressionKindMap = map[string]expressionKind{
+	rollStr: plainExpression,
+	hitStr:  attackExpression,
+	dmgStr:  damageExpression,
+}
+
+// expressionKind string, such as "hit".
+func (kind expressionKind) String() string {
+	return rollAttributeMapKey(expressionKindMap, kind)
+}
+
+// Represents a sequence of DiceRolls and rollFormulas.
 type rollingExpression struct {
 	diceRolls []DiceRoll
+	formulas  []rollFormula
+	kind      expressionKind
 }
 
 // Constructor of rollingExpression.
 func newRollingExpression(diceRolls ...DiceRoll) *rollingExpression {
-	return &rollingExpression{append(make([]DiceRoll, 0), diceRolls...)}
+	return &rollingExpression{append(make([]DiceRoll, 0), diceRolls...), make([]rollFormula, 0), plainExpression}
+}
+
+// Returns true if the rollingExpression has neither DiceRolls nor rollFormulas.
+func (rollExpr rollingExpression) isEmpty() bool {
+	return len(rollExpr.diceRolls) == 0 && len(rollExpr.formulas) == 0
 }
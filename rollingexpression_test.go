@@ -16,6 +16,8 @@ func TestRollingExpressionWithValidValues(t *testing.T) {
 	for i := range validRollArgsAttribs {
 		if rollAttrib := checkForRollAttribute(validRollArgsAttribs[i]); rollAttrib > 0 {
 			rollAttribs.setRollAttrib(rollAttrib)
+		} else if keepDropAlias := checkForKeepDropAlias(validRollArgsAttribs[i]); keepDropAlias != nil {
+			rollAttribs.keepDrop = append(rollAttribs.keepDrop, *keepDropAlias)
 		} else {
 			t.Fatalf("Valid roll attrib %s has no matching rollAttributes value", validRollArgsAttribs[i])
 		}
@@ -23,6 +25,7 @@ func TestRollingExpressionWithValidValues(t *testing.T) {
 		for i := range validDiceRollsValues {
 			diceRoll := validDiceRollsValues[i].diceRoll
 			diceRoll.rollAttribs.setRollAttrib(maps.Keys(rollAttribs.attribs)...)
+			diceRoll.rollAttribs.keepDrop = rollAttribs.keepDrop
 			rollExpr.diceRolls = append(rollExpr.diceRolls, diceRoll)
 		}
 
@@ -53,7 +56,7 @@ func TestRollingExpressionWithInvalidValues(t *testing.T) {
 	rollExpr := newRollingExpression()
 
 	for i := range invalidRollArgsAttribs {
-		if checkForRollAttribute(invalidRollArgsAttribs[i]) > 0 {
+		if checkForRollAttribute(invalidRollArgsAttribs[i]) > 0 || checkForKeepDropAlias(invalidRollArgsAttribs[i]) != nil {
 			t.Fatalf("Invalid roll attrib %s has matching rollAttributes value", invalidRollArgsAttribs[i])
 		}
 	}
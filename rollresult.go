@@ -1,49 +1,93 @@
 package diceroller
 
-import "fmt"
+import (
+	"fmt"
+	"slices"
+)
 
 // Results of performing a rollingExpression.
-type rollResult struct {
-	results []diceRollResult
+type RollResult struct {
+	results        []DiceRollResult
+	formulaResults []FormulaResult
+	seed           *RollSeed      // Seed and stream position used, nil when not rolled by a seeded Roller
+	kind           expressionKind // Kind of the performed rollingExpression
 }
 
-// Constructor of rollResult.
-func newRollResult() *rollResult {
-	return &rollResult{make([]diceRollResult, 0)}
+// Constructor of RollResult.
+func newRollResult() *RollResult {
+	return &RollResult{make([]DiceRollResult, 0), make([]FormulaResult, 0), nil, plainExpression}
 }
 
-// Sums multiple rollResult.
-func RollResultsSum(rollResults ...rollResult) (sum int) {
+// Sums multiple RollResult.
+func RollResultsSum(rollResults ...RollResult) (sum int) {
 	for e := range rollResults {
 		sum += rollResults[e].Sum()
 	}
 	return
 }
 
-func (rollResult rollResult) Sum() int {
-	return diceRollResultsSum(rollResult.results...)
+func (rollResult RollResult) Sum() int {
+	sum := diceRollResultsSum(rollResult.results...)
+	for i := range rollResult.formulaResults {
+		sum += rollResult.formulaResults[i].sum
+	}
+	return sum
+}
+
+// Returns the DiceRoll results of the rolling expression, formulas excluded.
+func (rollResult RollResult) Results() []DiceRollResult {
+	return slices.Clone(rollResult.results)
+}
+
+// Returns the formula results of the rolling expression.
+func (rollResult RollResult) FormulaResults() []FormulaResult {
+	return slices.Clone(rollResult.formulaResults)
+}
+
+// Returns the RollSeed to replay this RollResult. Returns false if not rolled by a seeded Roller.
+func (rollResult RollResult) Seed() (RollSeed, bool) {
+	if rollResult.seed == nil {
+		return RollSeed{}, false
+	}
+	return *rollResult.seed, true
 }
 
 // Formatted result output.
-func (rollResult rollResult) String() string {
+func (rollResult RollResult) String() string {
 	resultStr := "Roll result : \n" // add attribs to string
 	for i := range rollResult.results {
 		resultStr += rollResult.results[i].String()
 	}
-	resultStr += fmt.Sprintf("Roll results sum: %d \n", diceRollResultsSum(rollResult.results...))
+	for i := range rollResult.formulaResults {
+		resultStr += rollResult.formulaResults[i].String()
+	}
+	resultStr += fmt.Sprintf("Roll results sum: %d \n", rollResult.Sum())
 	return resultStr
 }
 
-// Detects a critical hit.
-func (rollResult rollResult) detectScoredCritHit() bool {
-	critHit := false
-	for i := range rollResult.results {
-		result := rollResult.results[i]
+// Returns true if a DiceRoll of the RollResult scored a critical hit.
+func (rollResult RollResult) CritHit() bool {
+	return rollResult.anyDiceRollResult(DiceRollResult.CritHit)
+}
+
+// Returns true if a DiceRoll of the RollResult scored a critical fail.
+func (rollResult RollResult) CritFail() bool {
+	return rollResult.anyDiceRollResult(DiceRollResult.CritFail)
+}
 
-		if result.hasScoredCritHit() {
-			critHit = true
-			break
+// Returns true if matches is true for any DiceRollResult, formula DiceRolls included.
+func (rollResult RollResult) anyDiceRollResult(matches func(DiceRollResult) bool) bool {
+	for i := range rollResult.results {
+		if matches(rollResult.results[i]) {
+			return true
+		}
+	}
+	for f := range rollResult.formulaResults {
+		for i := range rollResult.formulaResults[f].results {
+			if matches(rollResult.formulaResults[f].results[i]) {
+				return true
+			}
 		}
 	}
-	return critHit
+	return false
 }
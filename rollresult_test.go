@@ -21,3 +21,17 @@ func FuzzRollResultSum(f *testing.F) {
 		RollResultsSum(rollResults...)
 	})
 }
+
+func TestRollResultAccessors(t *testing.T) {
+	results, argErrs := NewRoller(maxSource{}).PerformRollArgs("2d6", "(1d4+1)*2")
+	if len(argErrs) > 0 {
+		t.Fatalf("Unexpected errors: %v", argErrs)
+	}
+	if len(results[0].Results()) != 1 || len(results[0].FormulaResults()) != 1 {
+		t.Fatalf("Unexpected results %s", results[0])
+	}
+	formula := results[0].FormulaResults()[0]
+	if formula.Formula() != "(1d4+1)*2" || formula.Sum() != 10 || len(formula.Results()) != 1 {
+		t.Fatalf("Formula %s summed to %d", formula.Formula(), formula.Sum())
+	}
+}
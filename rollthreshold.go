@@ -0,0 +1,95 @@
+package diceroller
+
+import (
+	"fmt"
+	"strings"
+)
+
+type compareOp int
+
+// compareOp values. 0 is invalid.
+const (
+	compareEqual        compareOp = iota + 1
+	compareGreater      compareOp = iota + 1
+	compareGreaterEqual compareOp = iota + 1
+	compareLess         compareOp = iota + 1
+	compareLessEqual    compareOp = iota + 1
+)
+
+// compareOp strings, longest first so ">=" is matched before ">".
+var compareOpStrs = []struct {
+	str string
+	op  compareOp
+}{
+	{">=", compareGreaterEqual},
+	{"<=", compareLessEqual},
+	{">", compareGreater},
+	{"<", compareLess},
+	{"=", compareEqual},
+}
+
+// A rollThreshold compares die rolls to a value, such as ">5" or "1".
+type rollThreshold struct {
+	op    compareOp // Comparison to apply, equal when no comparison symbol is given
+	value int       // Value die rolls are compared to
+}
+
+// Parses a threshold string such as ">=5" or "1". Returns a rollThreshold if valid, an error if invalid.
+func parseRollThreshold(thresholdStr string) (*rollThreshold, error) {
+	op := compareEqual
+	for i := range compareOpStrs {
+		if strings.HasPrefix(thresholdStr, compareOpStrs[i].str) {
+			op = compareOpStrs[i].op
+			thresholdStr = strings.TrimPrefix(thresholdStr, compareOpStrs[i].str)
+			break
+		}
+	}
+
+	value, argErr := parseRollArgSlice(thresholdStr)
+	if argErr != nil {
+		return nil, fmt.Errorf("invalid threshold: %w", argErr)
+	}
+
+	return &rollThreshold{op, value}, nil
+}
+
+// Returns true if roll matches the threshold.
+func (threshold rollThreshold) matches(roll int) bool {
+	matches := false
+	switch threshold.op {
+	case compareEqual:
+		matches = roll == threshold.value
+	case compareGreater:
+		matches = roll > threshold.value
+	case compareGreaterEqual:
+		matches = roll >= threshold.value
+	case compareLess:
+		matches = roll < threshold.value
+	case compareLessEqual:
+		matches = roll <= threshold.value
+	}
+	return matches
+}
+
+// Returns the ammount of faces of a diceSize die matching the threshold.
+func (threshold rollThreshold) matchingFaces(diceSize int) (faces int) {
+	for face := 1; face <= diceSize; face++ {
+		if threshold.matches(face) {
+			faces++
+		}
+	}
+	return
+}
+
+// Threshold string, such as ">=5". Equal thresholds have no comparison symbol.
+func (threshold rollThreshold) String() string {
+	opStr := ""
+	if threshold.op != compareEqual {
+		for i := range compareOpStrs {
+			if compareOpStrs[i].op == threshold.op {
+				opStr = compareOpStrs[i].str
+			}
+		}
+	}
+	return fmt.Sprintf("%s%d", opStr, threshold.value)
+}
@@ -0,0 +1,44 @@
+package diceroller
+
+import (
+	"testing"
+)
+
+func TestRollThresholdMatches(t *testing.T) {
+	// Faces of a d6 matching each threshold
+	wantedFaces := map[string]int{
+		"6":   1,
+		"=2":  1,
+		">4":  2,
+		">=4": 3,
+		"<3":  2,
+		"<=3": 3,
+		">6":  0,
+	}
+
+	for thresholdStr, faces := range wantedFaces {
+		threshold, argErr := parseRollThreshold(thresholdStr)
+		if argErr != nil {
+			t.Fatalf("Valid threshold %s returned an error: %s", thresholdStr, argErr.Error())
+		}
+		if matching := threshold.matchingFaces(6); matching != faces {
+			t.Fatalf("Threshold %s matches %d faces of a d6, wanted %d", thresholdStr, matching, faces)
+		}
+	}
+}
+
+func TestInvalidRollThreshold(t *testing.T) {
+	for _, thresholdStr := range []string{"", ">", "=>3", "<<2", "123456", "a"} {
+		if _, argErr := parseRollThreshold(thresholdStr); argErr == nil {
+			t.Fatalf("Invalid threshold %s did not generate an error", thresholdStr)
+		}
+	}
+}
+
+func TestRollThresholdString(t *testing.T) {
+	for _, thresholdStr := range []string{"6", ">4", ">=4", "<3", "<=3"} {
+		if threshold, _ := parseRollThreshold(thresholdStr); threshold.String() != thresholdStr {
+			t.Fatalf("Threshold = %s, wanted %s", threshold, thresholdStr)
+		}
+	}
+}
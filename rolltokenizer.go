@@ -0,0 +1,110 @@
+package diceroller
+
+import (
+	"fmt"
+	"regexp"
+	"unicode"
+)
+
+type tokenKind int
+
+// tokenKind values. 0 is invalid.
+const (
+	numberToken tokenKind = iota + 1
+	diceToken   tokenKind = iota + 1
+	plusToken   tokenKind = iota + 1
+	minusToken  tokenKind = iota + 1
+	timesToken  tokenKind = iota + 1
+	divideToken tokenKind = iota + 1
+	openToken   tokenKind = iota + 1
+	closeToken  tokenKind = iota + 1
+)
+
+// Single character tokens.
+var symbolTokenMap = map[rune]tokenKind{
+	'+': plusToken,
+	'-': minusToken,
+	'*': timesToken,
+	'/': divideToken,
+	'(': openToken,
+	')': closeToken,
+}
+
+// Returns the character of a single character token kind, 0 for others.
+func (kind tokenKind) symbol() rune {
+	for symbol, symbolKind := range symbolTokenMap {
+		if symbolKind == kind {
+			return symbol
+		}
+	}
+	return 0
+}
+
+// Threshold regex, comparison symbol is optional
+const thresholdFormat string = `(?:[<>]=?|=)?\d+`
+
+// Dice token regex, a dice term such as "4d6kh3" along with its rerolls, explode, keep or drop, success counting and critical range
+const diceTokenFormat string = `^(\d+)?[dD](\d+)` +
+	`(?:([rR][oO]?)(` + thresholdFormat + `))?` +
+	`(?:(!!|![pP]|!)(` + thresholdFormat + `)?)?` +
+	`(?:([kKdD][hHlL])(\d+))?` +
+	`(?:((?:[<>]=?|=)\d+)(?:[fF](` + thresholdFormat + `))?)?` +
+	`(?:[cC][sS](` + thresholdFormat + `))?(?:[cC][fF](` + thresholdFormat + `))?`
+
+// Number token regex
+const numberTokenFormat string = `^\d+`
+
+var diceTokenRegex = regexp.MustCompile(diceTokenFormat)
+var numberTokenRegex = regexp.MustCompile(numberTokenFormat)
+
+// A rollToken is a lexical token of a RollArg.
+type rollToken struct {
+	kind    tokenKind
+	text    string
+	offset  int      // Character offset of the token in the RollArg
+	matches []string // Dice token regex matches, nil for other tokens
+}
+
+// Splits rollArg into rollTokens. Whitespaces are ignored. Returns a ParseError on unexpected characters.
+func tokenizeRollArg(rollArg string) (tokens []rollToken, err error) {
+	for offset := 0; offset < len(rollArg); {
+		remaining := rollArg[offset:]
+		char := rune(rollArg[offset])
+
+		if unicode.IsSpace(char) {
+			offset++
+		} else if kind, found := symbolTokenMap[char]; found {
+			tokens = append(tokens, rollToken{kind, string(char), offset, nil})
+			offset++
+		} else if matches := diceTokenRegex.FindStringSubmatch(remaining); matches != nil {
+			tokens = append(tokens, rollToken{diceToken, matches[0], offset, matches})
+			offset += len(matches[0])
+		} else if number := numberTokenRegex.FindString(remaining); len(number) > 0 {
+			tokens = append(tokens, rollToken{numberToken, number, offset, nil})
+			offset += len(number)
+		} else {
+			return nil, newParseError(offset, fmt.Errorf("unexpected character %q", char))
+		}
+	}
+
+	return tokens, nil
+}
+
+// Returns true if tokens match the legacy "[+|-][X]dY[+|-Z]" RollArg format, where a leading minus
+// negates the whole DiceRoll, modifier included.
+func isLegacyDiceRollTokens(tokens []rollToken) bool {
+	if len(tokens) > 0 && (tokens[0].kind == plusToken || tokens[0].kind == minusToken) {
+		tokens = tokens[1:]
+	}
+
+	switch len(tokens) {
+	case 1:
+		return tokens[0].kind == diceToken
+	case 3:
+		return tokens[0].kind == diceToken &&
+			(tokens[1].kind == plusToken || tokens[1].kind == minusToken) &&
+			tokens[2].kind == numberToken
+	}
+
+	return false
+}
@@ -0,0 +1,61 @@
+package diceroller
+
+import (
+	"testing"
+)
+
+func TestTokenizeRollArg(t *testing.T) {
+	tokens, argErr := tokenizeRollArg("(4d6kh3 + 2) * -d8!/3")
+	if argErr != nil {
+		t.Fatalf("Valid RollArg returned an error: %s", argErr.Error())
+	}
+
+	wantedKinds := []tokenKind{openToken, diceToken, plusToken, numberToken, closeToken, timesToken, minusToken, diceToken, divideToken, numberToken}
+	if len(tokens) != len(wantedKinds) {
+		t.Fatalf("Tokenized %d tokens, wanted %d", len(tokens), len(wantedKinds))
+	}
+	for i := range tokens {
+		if tokens[i].kind != wantedKinds[i] {
+			t.Fatalf("Token %d %s kind = %d, wanted %d", i, tokens[i].text, tokens[i].kind, wantedKinds[i])
+		}
+	}
+
+	if tokens[1].text != "4d6kh3" || tokens[1].offset != 1 || tokens[7].text != "d8!" || tokens[7].offset != 16 {
+		t.Fatalf("Dice tokens = %s at %d and %s at %d", tokens[1].text, tokens[1].offset, tokens[7].text, tokens[7].offset)
+	}
+}
+
+func TestTokenizeInvalidRollArg(t *testing.T) {
+	for _, rollArg := range []string{"1d6 % 2", "1d6+x", "patate", "1d6!r1", "2d6=>3"} {
+		if _, argErr := tokenizeRollArg(rollArg); argErr == nil {
+			t.Fatalf("Invalid RollArg %s tokenized without error", rollArg)
+		}
+	}
+}
+
+func TestIsLegacyDiceRollTokens(t *testing.T) {
+	legacy := map[string]bool{
+		"1d6":      true,
+		"-5d6-1":   true,
+		"+d20+3":   true,
+		"4d6kh3-1": true,
+		"1d6+1+1":  false,
+		"1+1d6":    false,
+		"1d6*2":    false,
+		"-(1d6)":   false,
+	}
+
+	for rollArg, wanted := range legacy {
+		tokens, _ := tokenizeRollArg(rollArg)
+		if isLegacy := isLegacyDiceRollTokens(tokens); isLegacy != wanted {
+			t.Fatalf("RollArg %s legacy = %t, wanted %t", rollArg, isLegacy, wanted)
+		}
+	}
+}
+
+func FuzzTokenizeRollArg(f *testing.F) {
+	f.Add("(1d8+2)*2")
+	f.Fuzz(func(t *testing.T, fuzzedRollArg string) {
+		tokenizeRollArg(fuzzedRollArg)
+	})
+}
@@ -0,0 +1,141 @@
+package diceroller
+
+import (
+	"fmt"
+	"slices"
+)
+
+// A SaveRule selects the damage dealt to targets passing their saving throw.
+type SaveRule int
+
+// SaveRule values. 0 is invalid.
+const (
+	SaveHalf    SaveRule = iota + 1 // Half damage on a successful save, such as Fireball
+	SaveNegates SaveRule = iota + 1 // No damage on a successful save
+)
+
+// A SaveTarget is a creature rolling a saving throw.
+type SaveTarget struct {
+	Name     string // Name reported in the results
+	Modifier int    // Saving throw modifier added to the d20
+	Evasion  bool   // Against SaveHalf damage, no damage on a successful save and half on a failed one
+}
+
+// Results of a target saving throw.
+type SaveResult struct {
+	target SaveTarget
+	save   DiceRollResult // Saving throw d20 roll
+	dc     int            // Difficulty Class to meet or beat
+	saved  bool           // True if the save met or beat the DC
+	damage []RollResult   // Damage results, rolled once and shared by every target
+	taken  int            // Damage taken by the target
+}
+
+// Resolves a saving throw of each target against dc and applies the damage of damageArgs, rolled once for every target,
+// according to rule. Returns a SaveResult array, one per target, and an error array for invalid RollArgs or targets.
+func ResolveSavingThrows(dc int, rule SaveRule, targets []SaveTarget, damageArgs ...string) ([]SaveResult, []error) {
+	return defaultRoller.ResolveSavingThrows(dc, rule, targets, damageArgs...)
+}
+
+// Resolves a saving throw of each target against dc and applies the damage of damageArgs, rolled once for every target,
+// according to rule. Returns a SaveResult array, one per target, and an error array for invalid RollArgs or targets.
+// In strict mode, returns no SaveResult if anything is invalid.
+func (roller *Roller) ResolveSavingThrows(dc int, rule SaveRule, targets []SaveTarget, damageArgs ...string) (results []SaveResult, errs []error) {
+	if rule != SaveHalf && rule != SaveNegates {
+		return nil, []error{fmt.Errorf("invalid save rule %d", rule)}
+	}
+
+	if roller.strict() {
+		rollExprs, argErrs := parseRollArgs(damageArgs...)
+		errs = append(argErrs, validateRollingExpressions(rollExprs...)...)
+		if errs = append(errs, validateSaveTargets(targets)...); len(errs) > 0 {
+			return nil, errs
+		}
+	}
+
+	damage, errs := roller.PerformRollArgs(damageArgs...)
+	if roller.strict() && len(errs) > 0 {
+		return nil, errs
+	}
+	fullDamage := RollResultsSum(damage...)
+
+	for i := range targets {
+		save, saveErr := roller.validateAndperformRoll(*newSaveDiceRoll(targets[i].Modifier))
+		if saveErr != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", targets[i].Name, saveErr))
+			continue
+		}
+
+		saved := save.sum >= dc
+		results = append(results, SaveResult{targets[i], *save, dc, saved, damage, damageTaken(fullDamage, rule, saved, targets[i].Evasion)})
+	}
+
+	return results, errs
+}
+
+// Validates the saving throw DiceRoll of each target. Returns an error array for invalid targets.
+func validateSaveTargets(targets []SaveTarget) (errs []error) {
+	for i := range targets {
+		if saveErr := validateDiceRoll(*newSaveDiceRoll(targets[i].Modifier)); saveErr != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", targets[i].Name, saveErr))
+		}
+	}
+	return errs
+}
+
+// Returns the 1d20 DiceRoll of a saving throw with modifier.
+func newSaveDiceRoll(modifier int) *DiceRoll {
+	return &DiceRoll{1, critDiceSize, modifier, newRollAttributes()}
+}
+
+// Returns the damage taken out of fullDamage according to rule, the saving throw and evasion.
+func damageTaken(fullDamage int, rule SaveRule, saved bool, evasion bool) int {
+	taken := fullDamage
+	switch {
+	case saved && (rule == SaveNegates || evasion):
+		taken = 0
+	case saved, evasion && rule == SaveHalf:
+		taken = fullDamage / 2 // Rounded down, 1 damage halved is 0
+	}
+	return taken
+}
+
+// Returns the target of the saving throw.
+func (result SaveResult) Target() SaveTarget {
+	return result.target
+}
+
+// Returns the saving throw sum.
+func (result SaveResult) SaveSum() int {
+	return result.save.sum
+}
+
+// Returns the saving throw d20 roll result.
+func (result SaveResult) SaveRoll() DiceRollResult {
+	return result.save
+}
+
+// Returns the damage results, shared by every target.
+func (result SaveResult) DamageResults() []RollResult {
+	return slices.Clone(result.damage)
+}
+
+// Returns true if the target passed its saving throw.
+func (result SaveResult) Saved() bool {
+	return result.saved
+}
+
+// Returns the damage taken by the target.
+func (result SaveResult) Damage() int {
+	return result.taken
+}
+
+// Human readable SaveResult string.
+func (result SaveResult) String() string {
+	outcome := "failed"
+	if result.saved {
+		outcome = "saved"
+	}
+	return fmt.Sprintf("%s rolled %d against DC %d: %s, takes %d of %d damage\n",
+		result.target.Name, result.save.sum, result.dc, outcome, result.taken, RollResultsSum(result.damage...))
+}
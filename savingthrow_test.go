@@ -0,0 +1,67 @@
+package diceroller
+
+import (
+	"testing"
+)
+
+func TestResolveSavingThrows(t *testing.T) {
+	// Max source rolls natural 20s, against DC 25 only targets with a +5 save modifier succeed
+	targets := []SaveTarget{
+		{"Fighter", 0, false},
+		{"Wizard", 5, false},
+		{"Rogue", 5, true},
+		{"Monk", 0, true},
+		{"Dragon", 500000, false}}
+
+	wantedDamage := map[SaveRule][]int{
+		SaveHalf:    {48, 24, 0, 24},
+		SaveNegates: {48, 0, 0, 48},
+	}
+
+	for rule, damage := range wantedDamage {
+		results, saveErrs := NewRoller(maxSource{}).ResolveSavingThrows(25, rule, targets, "spell", "8d6")
+		if len(saveErrs) != 1 {
+			t.Fatalf("Out of bounds save modifier returned %d errors, wanted 1", len(saveErrs))
+		}
+		if len(results) != len(damage) {
+			t.Fatalf("Resolved %d saving throws, wanted %d", len(results), len(damage))
+		}
+		for i := range results {
+			if results[i].Saved() != (results[i].Target().Modifier == 5) || results[i].Damage() != damage[i] {
+				t.Fatalf("Save rule %d: %s", rule, results[i])
+			}
+		}
+	}
+
+	if _, saveErrs := ResolveSavingThrows(15, 0, targets, "8d6"); len(saveErrs) == 0 {
+		t.Fatal("Invalid save rule returned no error")
+	}
+}
+
+func TestDamageTaken(t *testing.T) {
+	// Odd damage rounds down when halved
+	if taken := damageTaken(21, SaveHalf, true, false); taken != 10 {
+		t.Fatalf("Saved half of 21 damage is %d, wanted 10", taken)
+	}
+	if taken := damageTaken(21, SaveNegates, false, true); taken != 21 {
+		t.Fatalf("Evasion halved failed save negates damage to %d", taken)
+	}
+
+	// Halved 0 and 1 damage is 0, saved or with Evasion
+	wantedTaken := []struct {
+		fullDamage int
+		saved      bool
+		evasion    bool
+	}{
+		{0, true, false},
+		{1, true, false},
+		{0, false, true},
+		{1, false, true},
+	}
+	for i := range wantedTaken {
+		if taken := damageTaken(wantedTaken[i].fullDamage, SaveHalf, wantedTaken[i].saved, wantedTaken[i].evasion); taken != 0 {
+			t.Fatalf("Halved %d damage with save %t and Evasion %t is %d, wanted 0",
+				wantedTaken[i].fullDamage, wantedTaken[i].saved, wantedTaken[i].evasion, taken)
+		}
+	}
+}
@@ -0,0 +1,311 @@
+package diceroller
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"sync"
+)
+
+// Amount of trials a simulation worker performs between progress reports and cancellation checks.
+const simulationBatchSize int = 1024
+
+// Options of a simulation run.
+type SimulationOptions struct {
+	Workers  int                        // Amount of worker goroutines, runtime.NumCPU() if 0 or less
+	Seed     uint64                     // Seed of the worker random sources, random if 0. Same seed and Workers give same results
+	Progress func(done int, trials int) // Called with the amount of trials done as workers progress, from the calling goroutine. Can be nil
+}
+
+// Results of a simulation run.
+type Simulation struct {
+	Total       SimulationStats   // Stats of the total sum of the RollArgs
+	Expressions []SimulationStats // Stats of each rolling expression sum, in RollArgs order
+	Failed      int               // Amount of trials left out of the stats because rolling failed, such as dividing by zero
+	failedErr   error             // First error of the failed trials
+}
+
+// Statistics of simulated outcomes.
+type SimulationStats struct {
+	histogram map[int]int // Amount of trials for each outcome
+	trials    int
+}
+
+// Performs trials of rollArgs with the default simulation options. Returns a Simulation and an error array for invalid RollArgs.
+func Simulate(rollArgs []string, trials int) (*Simulation, []error) {
+	return defaultRoller.Simulate(rollArgs, trials)
+}
+
+// Performs trials of rollArgs across worker goroutines, each with its own random source. Returns a Simulation and
+// an error array for invalid RollArgs. When ctx is done, returns the trials done so far along with the ctx error.
+func SimulateContext(ctx context.Context, rollArgs []string, trials int, options SimulationOptions) (*Simulation, []error) {
+	return defaultRoller.SimulateContext(ctx, rollArgs, trials, options)
+}
+
+// Performs trials of rollArgs with the default simulation options and the Roller settings.
+// Returns a Simulation and an error array for invalid RollArgs.
+func (roller *Roller) Simulate(rollArgs []string, trials int) (*Simulation, []error) {
+	return roller.SimulateContext(context.Background(), rollArgs, trials, SimulationOptions{})
+}
+
+// Performs trials of rollArgs across worker goroutines, each with its own random source and the critical range,
+// critical damage policy, evaluation mode and RollPipeline of the Roller. The Roller source is left untouched.
+// Returns a Simulation and an error array for invalid RollArgs. Trials failing to roll are left out of the stats,
+// along with an error wrapping the first failure. When ctx is done, returns the trials done so far along with the ctx error.
+func (roller *Roller) SimulateContext(ctx context.Context, rollArgs []string, trials int, options SimulationOptions) (*Simulation, []error) {
+	rollExprs, argErrs := parseRollArgs(rollArgs...)
+
+	workers := options.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	workers = max(min(workers, trials), 1)
+
+	seed := options.Seed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+
+	partials := make([]*Simulation, workers)
+	progress := make(chan int)
+	var waitGroup sync.WaitGroup
+	for w := range workers {
+		workerTrials := trials / workers
+		if w < trials%workers {
+			workerTrials++
+		}
+
+		workerRoller := *roller
+		workerRoller.rand, workerRoller.seeded = rand.New(rand.NewPCG(seed, uint64(w))), nil
+
+		partials[w] = newSimulation(len(rollExprs))
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			partials[w].run(ctx, &workerRoller, rollExprs, workerTrials, progress)
+		}()
+	}
+	go func() {
+		waitGroup.Wait()
+		close(progress)
+	}()
+
+	// Progress reports from the calling goroutine
+	for done := 0; ; {
+		batch, running := <-progress
+		if !running {
+			break
+		}
+		done += batch
+		if options.Progress != nil {
+			options.Progress(done, trials)
+		}
+	}
+
+	simulation := newSimulation(len(rollExprs))
+	for w := range partials {
+		simulation.merge(partials[w])
+	}
+
+	if simulation.Failed > 0 {
+		argErrs = append(argErrs, fmt.Errorf("%d trials failed and were left out: %w", simulation.Failed, simulation.failedErr))
+	}
+	if ctx.Err() != nil {
+		argErrs = append(argErrs, ctx.Err())
+	}
+	return simulation, argErrs
+}
+
+// Simulation constructor, with stats for exprCount rolling expressions.
+func newSimulation(exprCount int) *Simulation {
+	simulation := &Simulation{newSimulationStats(), make([]SimulationStats, exprCount), 0, nil}
+	for e := range simulation.Expressions {
+		simulation.Expressions[e] = newSimulationStats()
+	}
+	return simulation
+}
+
+// Performs trials of rollExprs using roller, reporting progress after each batch. Trials failing to roll are
+// counted as failed instead. Stops when ctx is done.
+func (simulation *Simulation) run(ctx context.Context, roller *Roller, rollExprs []rollingExpression, trials int, progress chan<- int) {
+	for done := 0; done < trials && ctx.Err() == nil; {
+		batch := min(simulationBatchSize, trials-done)
+		for t := 0; t < batch; t++ {
+			results, diceErrs := roller.performRollingExpressions(rollExprs...)
+			if len(diceErrs) > 0 {
+				simulation.fail(diceErrs[0])
+				continue
+			}
+			total := 0
+			for e := range results {
+				simulation.Expressions[e].add(results[e].Sum())
+				total += results[e].Sum()
+			}
+			simulation.Total.add(total)
+		}
+		done += batch
+		progress <- batch
+	}
+}
+
+// Counts a failed trial, keeping the first error.
+func (simulation *Simulation) fail(err error) {
+	simulation.Failed++
+	if simulation.failedErr == nil {
+		simulation.failedErr = err
+	}
+}
+
+// Adds the trials of other to the Simulation.
+func (simulation *Simulation) merge(other *Simulation) {
+	simulation.Total.merge(other.Total)
+	for e := range simulation.Expressions {
+		simulation.Expressions[e].merge(other.Expressions[e])
+	}
+	if other.Failed > 0 {
+		simulation.Failed += other.Failed - 1
+		simulation.fail(other.failedErr)
+	}
+}
+
+// Human readable Simulation string.
+func (simulation Simulation) String() string {
+	simulationStr := ""
+	for e := range simulation.Expressions {
+		simulationStr += fmt.Sprintf("Expression %d: %s\n", e+1, simulation.Expressions[e])
+	}
+	simulationStr += fmt.Sprintf("Total: %s\n", simulation.Total)
+	if simulation.Failed > 0 {
+		simulationStr += fmt.Sprintf("Failed: %d trials\n", simulation.Failed)
+	}
+	return simulationStr
+}
+
+// SimulationStats constructor.
+func newSimulationStats() SimulationStats {
+	return SimulationStats{make(map[int]int), 0}
+}
+
+// Adds a trial outcome.
+func (stats *SimulationStats) add(outcome int) {
+	stats.histogram[outcome]++
+	stats.trials++
+}
+
+// Adds the trials of other to the stats.
+func (stats *SimulationStats) merge(other SimulationStats) {
+	for outcome, count := range other.histogram {
+		stats.histogram[outcome] += count
+	}
+	stats.trials += other.trials
+}
+
+// Returns the amount of trials.
+func (stats SimulationStats) Trials() int {
+	return stats.trials
+}
+
+// Returns the amount of trials for each outcome.
+func (stats SimulationStats) Histogram() map[int]int {
+	histogram := make(map[int]int)
+	for outcome, count := range stats.histogram {
+		histogram[outcome] = count
+	}
+	return histogram
+}
+
+// Returns the outcomes in ascending order.
+func (stats SimulationStats) outcomes() []int {
+	outcomes := make([]int, 0, len(stats.histogram))
+	for outcome := range stats.histogram {
+		outcomes = append(outcomes, outcome)
+	}
+	slices.Sort(outcomes)
+	return outcomes
+}
+
+// Returns the lowest outcome, 0 without trials.
+func (stats SimulationStats) Min() int {
+	if outcomes := stats.outcomes(); len(outcomes) > 0 {
+		return outcomes[0]
+	}
+	return 0
+}
+
+// Returns the highest outcome, 0 without trials.
+func (stats SimulationStats) Max() int {
+	if outcomes := stats.outcomes(); len(outcomes) > 0 {
+		return outcomes[len(outcomes)-1]
+	}
+	return 0
+}
+
+// Returns the mean outcome.
+func (stats SimulationStats) Mean() (mean float64) {
+	if stats.trials == 0 {
+		return 0
+	}
+	for outcome, count := range stats.histogram {
+		mean += float64(outcome) * float64(count)
+	}
+	return mean / float64(stats.trials)
+}
+
+// Returns the sample standard deviation of the outcomes.
+func (stats SimulationStats) StdDev() float64 {
+	if stats.trials < 2 {
+		return 0
+	}
+	mean, squares := stats.Mean(), 0.0
+	for outcome, count := range stats.histogram {
+		squares += (float64(outcome) - mean) * (float64(outcome) - mean) * float64(count)
+	}
+	return math.Sqrt(squares / float64(stats.trials-1))
+}
+
+// Returns the outcome below or at which percent of the trials fall, using the nearest rank.
+func (stats SimulationStats) Percentile(percent float64) int {
+	rank := max(int(math.Ceil(percent/100*float64(stats.trials))), 1)
+	seen := 0
+	outcomes := stats.outcomes()
+	for i := range outcomes {
+		seen += stats.histogram[outcomes[i]]
+		if seen >= rank {
+			return outcomes[i]
+		}
+	}
+	return stats.Max()
+}
+
+// Returns the fraction of trials with an outcome of value or more, estimating P(X >= value).
+func (stats SimulationStats) AtLeast(value int) float64 {
+	if stats.trials == 0 {
+		return 0
+	}
+	atLeast := 0
+	for outcome, count := range stats.histogram {
+		if outcome >= value {
+			atLeast += count
+		}
+	}
+	return float64(atLeast) / float64(stats.trials)
+}
+
+// Returns the confidence interval of the mean at the confidence level, such as 0.95, using the normal approximation.
+func (stats SimulationStats) MeanConfidenceInterval(confidence float64) (low float64, high float64) {
+	if stats.trials == 0 {
+		return 0, 0
+	}
+	margin := math.Sqrt2 * math.Erfinv(confidence) * stats.StdDev() / math.Sqrt(float64(stats.trials))
+	return stats.Mean() - margin, stats.Mean() + margin
+}
+
+// Human readable SimulationStats string.
+func (stats SimulationStats) String() string {
+	low, high := stats.MeanConfidenceInterval(0.95)
+	return fmt.Sprintf("Trials: %d Min: %d Max: %d Mean: %.3f (95%% CI %.3f-%.3f) StdDev: %.3f Median: %d",
+		stats.trials, stats.Min(), stats.Max(), stats.Mean(), low, high, stats.StdDev(), stats.Percentile(50))
+}
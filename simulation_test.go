@@ -0,0 +1,98 @@
+package diceroller
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"strings"
+	"testing"
+)
+
+func TestSimulate(t *testing.T) {
+	simulation, simErrs := SimulateContext(context.Background(), []string{"hit", "1d20", "dmg", "2d6"}, 50000, SimulationOptions{Workers: 4, Seed: 1})
+	if len(simErrs) > 0 {
+		t.Fatalf("Simulation errors: %v", simErrs)
+	}
+	if len(simulation.Expressions) != 2 || simulation.Total.Trials() != 50000 || simulation.Expressions[1].Trials() != 50000 {
+		t.Fatalf("Simulation has %d expressions and %d trials", len(simulation.Expressions), simulation.Total.Trials())
+	}
+
+	damage := simulation.Expressions[1]
+	if damage.Min() < 2 || damage.Max() > 24 || damage.Percentile(50) != 7 {
+		t.Fatalf("2d6 simulated stats are %s", damage)
+	}
+
+	// The exact mean falls in the confidence interval
+	distribution, _ := RollArgsDistribution("hit", "1d20", "dmg", "2d6")
+	if low, high := simulation.Total.MeanConfidenceInterval(0.999); distribution.Mean() < low || distribution.Mean() > high {
+		t.Fatalf("Exact mean %f out of simulated confidence interval %f-%f", distribution.Mean(), low, high)
+	}
+	if atLeast := simulation.Expressions[0].AtLeast(20); atLeast < 0.04 || atLeast > 0.06 {
+		t.Fatalf("1d20 simulated P(X >= 20) is %f", atLeast)
+	}
+}
+
+func TestSimulateReproducible(t *testing.T) {
+	options := SimulationOptions{Workers: 3, Seed: 42}
+	simulation, _ := SimulateContext(context.Background(), []string{"4d6kh3"}, 10000, options)
+	simulation2, _ := SimulateContext(context.Background(), []string{"4d6kh3"}, 10000, options)
+	if !maps.Equal(simulation.Total.Histogram(), simulation2.Total.Histogram()) {
+		t.Fatal("Simulations with the same seed and workers differ")
+	}
+}
+
+func TestRollerSimulate(t *testing.T) {
+	roller := NewSeededRoller(1)
+	roller.SetCritDamagePolicy(CritMaxPlusRoll, 0)
+	pipeline, _ := roller.RollPipeline().InsertAfter(ModifierStage, RollStage{"flat", func(state *RollState) {
+		state.SetSum(state.Sum() + 100)
+	}})
+	roller.SetRollPipeline(pipeline)
+
+	// Workers roll with the Roller settings, crit 1d6 being the max roll plus 1d6, then 100 more
+	simulation, simErrs := roller.Simulate([]string{"crit", "1d6"}, 5000)
+	if len(simErrs) > 0 || simulation.Total.Min() != 107 || simulation.Total.Max() != 112 {
+		t.Fatalf("Roller simulation of crit 1d6 is %s, errors: %v", simulation.Total, simErrs)
+	}
+	if roller.seeded.position != 0 {
+		t.Fatalf("Roller simulation moved the Roller source to position %d", roller.seeded.position)
+	}
+}
+
+func TestSimulateFailedTrials(t *testing.T) {
+	// Dividing by zero half the time
+	simulation, simErrs := SimulateContext(context.Background(), []string{"1d6/(1d2-1)"}, 10000, SimulationOptions{Workers: 3, Seed: 1})
+	if len(simErrs) != 1 || !strings.Contains(simErrs[0].Error(), "division by zero") {
+		t.Fatalf("Simulation dividing by zero returned errors %v, wanted a division by zero error", simErrs)
+	}
+	if simulation.Failed < 4000 || simulation.Failed > 6000 || simulation.Total.Trials()+simulation.Failed != 10000 {
+		t.Fatalf("Simulation dividing by zero failed %d trials and kept %d", simulation.Failed, simulation.Total.Trials())
+	}
+	if simulation.Total.Min() != 1 || simulation.Expressions[0].Histogram()[0] != 0 {
+		t.Fatalf("Failed trials were counted in the stats %s", simulation.Total)
+	}
+}
+
+func TestSimulateProgressAndCancel(t *testing.T) {
+	lastDone := 0
+	options := SimulationOptions{Workers: 2, Seed: 1, Progress: func(done int, trials int) {
+		if done < lastDone || trials != 5000 {
+			t.Fatalf("Progress went from %d to %d of %d trials", lastDone, done, trials)
+		}
+		lastDone = done
+	}}
+	if _, simErrs := SimulateContext(context.Background(), []string{"1d6"}, 5000, options); len(simErrs) > 0 || lastDone != 5000 {
+		t.Fatalf("Progress ended at %d trials, errors: %v", lastDone, simErrs)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	simulation, simErrs := SimulateContext(ctx, []string{"1d6"}, 1000000, SimulationOptions{})
+	if len(simErrs) != 1 || !errors.Is(simErrs[0], context.Canceled) || simulation.Total.Trials() == 1000000 {
+		t.Fatalf("Canceled simulation returned %d trials, errors: %v", simulation.Total.Trials(), simErrs)
+	}
+
+	if _, simErrs := Simulate([]string{"1d6", "0d6"}, 10); len(simErrs) == 0 {
+		t.Fatal("Invalid RollArg simulation returned no error")
+	}
+}
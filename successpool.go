@@ -0,0 +1,86 @@
+package diceroller
+
+import (
+	"fmt"
+)
+
+// Allowed failure string in a RollArg.
+const failureStr string = "f"
+
+// A successRule turns a DiceRoll into a dice pool counting successes instead of summing dice.
+type successRule struct {
+	target  rollThreshold  // Dice matching the target are successes
+	failure *rollThreshold // Dice matching the failure cancel a success, nil when failures aren't counted
+}
+
+// Parses success RollArg slices such as ">=8" and "1". Returns a successRule if valid, an error if invalid.
+func parseSuccessRule(targetStr string, failureStr string) (*successRule, error) {
+	target, argErr := parseRollThreshold(targetStr)
+	if argErr != nil {
+		return nil, argErr
+	}
+
+	rule := &successRule{*target, nil}
+	if len(failureStr) > 0 {
+		failure, argErr := parseRollThreshold(failureStr)
+		if argErr != nil {
+			return nil, argErr
+		}
+		rule.failure = failure
+	}
+
+	return rule, nil
+}
+
+// Validates a successRule. Exploding dice need a threshold, which the target would be read as otherwise.
+// Returns nil if valid, an error if invalid.
+func validateSuccessRule(rule *successRule, explode *explodeRule) error {
+	if rule != nil && explode != nil && explode.threshold == nil {
+		return fmt.Errorf("invalid success counting %s, exploding dice need a threshold such as %s10", rule, explode)
+	}
+	return nil
+}
+
+// Success string, such as ">4f1". Targets always have a comparison symbol.
+func (rule successRule) String() string {
+	successStr := rule.target.String()
+	if rule.target.op == compareEqual {
+		successStr = "=" + successStr
+	}
+	if rule.failure != nil {
+		successStr += failureStr + rule.failure.String()
+	}
+	return successStr
+}
+
+// Counts successes and failures of the kept dice and of their extra exploding dice. The sum becomes the net successes.
+func countSuccesses(diceRollResult *DiceRollResult, rule successRule) {
+	dice := append([]int{}, diceRollResult.dice...)
+
+	// Compounded extra dice are already part of the dice
+	if explode := diceRollResult.diceRoll.explodeRule(); explode != nil && explode.mode != explodeCompound {
+		for i := range diceRollResult.explosions {
+			dice = append(dice, diceRollResult.explosions[i].extra...)
+		}
+	}
+
+	for i := range dice {
+		if rule.target.matches(dice[i]) {
+			diceRollResult.successes++
+		} else if rule.failure != nil && rule.failure.matches(dice[i]) {
+			diceRollResult.failures++
+		}
+	}
+
+	diceRollResult.sum = diceRollResult.successes - diceRollResult.failures
+}
+
+// Human readable success counts string, replacing the sum in DiceRollResult strings.
+func successesString(diceRollResult DiceRollResult) string {
+	successesStr := fmt.Sprintf("  Successes: %d\n", diceRollResult.successes)
+	if diceRollResult.diceRoll.successRule().failure != nil {
+		successesStr += fmt.Sprintf("  Failures:  %d\n", diceRollResult.failures)
+	}
+	successesStr += fmt.Sprintf("  Net:       %d", diceRollResult.sum)
+	return successesStr
+}
@@ -0,0 +1,147 @@
+package diceroller
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// Valid success counting Roll Args
+var validSuccessRollArgs = []string{
+	"10d10>=8",
+	"12d6>4f1",
+	"5d10>7f<=2+1",
+	"8d6=6",
+	"6d10!10>=8",
+	"4d6kh3>3-1"}
+
+// Invalid success counting Roll Args
+var invalidSuccessRollArgs = []string{
+	"10d10>=",
+	"10d10f1",
+	"10d10>=8f",
+	"10d10>=8+1f1",
+	"10d10>=8>=9"}
+
+func TestParseValidSuccessRollArgs(t *testing.T) {
+	for i := range validSuccessRollArgs {
+		diceRoll, argErr := parseRollArg(validSuccessRollArgs[i])
+		if argErr != nil {
+			validArgParsingError(argErr, t)
+		}
+		if diceRoll.successRule() == nil {
+			t.Fatalf("RollArg %s parsed without a successRule", validSuccessRollArgs[i])
+		}
+		if reparsed, _ := parseRollArg(diceRoll.String()); reparsed == nil || reparsed.String() != diceRoll.String() {
+			t.Fatalf("DiceRoll %s string is not a valid RollArg", diceRoll)
+		}
+	}
+}
+
+func TestParseInvalidSuccessRollArgs(t *testing.T) {
+	for i := range invalidSuccessRollArgs {
+		if _, argErr := parseRollArg(invalidSuccessRollArgs[i]); argErr == nil {
+			invalidArgParsingError(invalidSuccessRollArgs[i], t)
+		}
+	}
+}
+
+func TestCountSuccesses(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		results, _ := PerformRollArgs("12d6>4f1", "adv", "crit", "5d10>=8+1")
+
+		pool := results[0].results[0]
+		successes, failures := 0, 0
+		for d := range pool.dice {
+			if pool.dice[d] > 4 {
+				successes++
+			} else if pool.dice[d] == 1 {
+				failures++
+			}
+		}
+		if pool.successes != successes || pool.failures != failures || pool.sum != successes-failures {
+			t.Fatalf("12d6>4f1 result = %s, wanted %d successes and %d failures", pool, successes, failures)
+		}
+
+		// Advantage and crit apply to pools too
+		advCritPool := results[1].results[0]
+		if len(advCritPool.dice) != 10 || len(advCritPool.advDisDropped) != 10 {
+			t.Fatalf("adv crit 5d10>=8+1 result = %s, wanted 10 kept and 10 dropped dice", advCritPool)
+		}
+		if advCritPool.sum != advCritPool.successes+1 {
+			t.Fatalf("adv crit 5d10>=8+1 result = %d, wanted %d", advCritPool.sum, advCritPool.successes+1)
+		}
+	}
+}
+
+func TestSuccessPoolDroppedDiceDropTheirChain(t *testing.T) {
+	// Max source explodes every die until the cap, only the kept chain counts
+	results, _ := NewRoller(maxSource{}).PerformRollArgs("3d10!10kl1>=8")
+	if pool := results[0].results[0]; pool.successes != 1+maxExplodeChainLength || len(pool.explosions) != 1 {
+		t.Fatalf("3d10!10kl1>=8 with max source counted %d successes with %d explosions, wanted %d and 1",
+			pool.successes, len(pool.explosions), 1+maxExplodeChainLength)
+	}
+
+	for i := 0; i < 100; i++ {
+		results, _ = PerformRollArgs("6d10!>9kl2>=8")
+		pool := results[0].results[0]
+
+		// Only kept dice above 9 exploded
+		exploded, successes := 0, 0
+		for d := range pool.dice {
+			if pool.dice[d] > 9 {
+				exploded++
+			}
+			if pool.dice[d] >= 8 {
+				successes++
+			}
+		}
+		for c := range pool.explosions {
+			for e := range pool.explosions[c].extra {
+				if pool.explosions[c].extra[e] >= 8 {
+					successes++
+				}
+			}
+		}
+		if len(pool.explosions) != exploded || pool.successes != successes {
+			t.Fatalf("6d10!>9kl2>=8 kept %v with explosions %v, counted %d successes, wanted %d", pool.dice, pool.explosions, pool.successes, successes)
+		}
+	}
+}
+
+func TestSuccessPoolCanBeZeroOrNegative(t *testing.T) {
+	results, _ := NewRoller(maxSource{}).PerformRollArgs("4d6<2", "roll", "4d6>=1f6")
+
+	if sum := results[0].Sum(); sum != 0 {
+		t.Fatalf("4d6<2 with max source result = %d, wanted 0", sum)
+	}
+	if sum := results[1].Sum(); sum != 4 {
+		t.Fatalf("4d6>=1f6 with max source result = %d, wanted 4", sum)
+	}
+
+	results, _ = NewRoller(maxSource{}).PerformRollArgs("4d6>6f6")
+	if sum := results[0].Sum(); sum != -4 {
+		t.Fatalf("4d6>6f6 with max source result = %d, wanted -4", sum)
+	}
+}
+
+func TestSuccessPoolString(t *testing.T) {
+	results, _ := PerformRollArgs("12d6>4f1")
+	resultStr := results[0].String()
+
+	for _, wanted := range []string{"Successes:", "Failures:", "Net:"} {
+		if !strings.Contains(resultStr, wanted) {
+			t.Fatalf("Roll result = %s, no match for %s", resultStr, wanted)
+		}
+	}
+	if strings.Contains(resultStr, "Sum:") {
+		t.Fatalf("Roll result = %s, wanted no Sum", resultStr)
+	}
+}
+
+func FuzzSuccessRollArg(f *testing.F) {
+	f.Add(10, 10, ">=8", "1")
+	f.Fuzz(func(t *testing.T, diceAmmount int, diceSize int, target string, failure string) {
+		PerformRollArgs(fmt.Sprintf("%dd%d%sf%s", diceAmmount, diceSize, target, failure))
+	})
+}